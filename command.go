@@ -0,0 +1,359 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ryokpen87044/bowlingScorer/scoring"
+)
+
+var errUsage = errors.New(`usage:
+  bowlingScorer [-db path] [-stream address]
+  bowlingScorer [-db path] <command>
+
+commands:
+  bowlingScorer score <notation>
+  bowlingScorer list
+  bowlingScorer show <player>
+  bowlingScorer stats <player>
+  bowlingScorer add <player> <notation>
+  bowlingScorer export [-frames] [-o file] <player>
+  bowlingScorer import <player> <file.csv>
+  bowlingScorer serve [-addr address]
+  bowlingScorer migrate
+  bowlingScorer validate [player...]
+  bowlingScorer history <player>
+  bowlingScorer rebuild [-at time] [-w] <player>
+  bowlingScorer handicap [-basis n] [-percent n] [-games n] [-round down|up|nearest] [-off] <player>
+  bowlingScorer league list
+  bowlingScorer league new [-games n] [-lane n] <league>
+  bowlingScorer league team <league> <team> <player>...
+  bowlingScorer league schedule [-rounds n] [-start date] [-every days] <league>
+  bowlingScorer league points [-game n] [-series n] [-blind pins] [-basis n] [-percent n] [-games n] [-round down|up|nearest] [-scratch] <league>
+  bowlingScorer league score [-team team] <league> <week> <player> <game>...
+  bowlingScorer league score -blind <league> <week> <player>
+  bowlingScorer league show <league> [week]`)
+
+func (m Model) load(name string) (Bowl, error) {
+	if !validName(name) {
+		return Bowl{}, fmt.Errorf("%w: %q", errInvalidName, name)
+	}
+	if _, err := m.store.Load(name); err != nil {
+		return Bowl{}, err
+	}
+	m.data = name
+	return m.read()
+}
+
+// loadClean loads a player that is about to be changed and saved. Unlike
+// load, nothing is repaired in memory. Problems in the archives are kept as
+// they are, as the repair scene would, but a game in progress that cannot be
+// kept is refused until the player is opened to repair or discard it.
+func (m Model) loadClean(name string) (Bowl, error) {
+	if !validName(name) {
+		return Bowl{}, fmt.Errorf("%w: %q", errInvalidName, name)
+	}
+	if _, err := m.store.Load(name); err != nil {
+		return Bowl{}, err
+	}
+	m.data = name
+	bowl, findings, err := m.inspect()
+	if err != nil {
+		return bowl, err
+	}
+	for _, f := range findings {
+		if f.fatal() {
+			return bowl, fmt.Errorf("%w: \"%s\", %s", errUnresolved, name, f)
+		}
+	}
+	return bowl, nil
+}
+
+// loadOrCreate loads a player to change, or starts a new one when there is no
+// such player. Any other failure is returned, so that nothing is ever saved
+// over data that could not be read or was never repaired.
+func (m Model) loadOrCreate(name string) (Bowl, error) {
+	bowl, err := m.loadClean(name)
+	if !errors.Is(err, errNoPlayer) {
+		return bowl, err
+	}
+	bowl = initBowl()
+	bowl.Name = name
+	return bowl, nil
+}
+
+// appendArchives adds finished games to a player, saving a new player first.
+func (m Model) appendArchives(name string, archives []Archive) (Bowl, error) {
+	var err error
+	if m.Bowl, err = m.loadOrCreate(name); err != nil {
+		return m.Bowl, err
+	}
+	if _, err := m.store.Load(m.Bowl.Name); errors.Is(err, errNoPlayer) {
+		if err := m.write(); err != nil {
+			return m.Bowl, err
+		}
+	}
+	for _, a := range archives {
+		past := m.Bowl
+		past.Discipline = a.Discipline
+		a.Handicap, _ = past.handicap()
+		if err := m.store.AppendArchive(m.Bowl.Name, a); err != nil {
+			return m.Bowl, err
+		}
+		before := m.Bowl
+		m.Bowl.Archives = append(m.Bowl.Archives, a)
+		m.noteArchive(a, before)
+	}
+	return m.Bowl, nil
+}
+func (m Model) exportCommand(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	frames := flags.Bool("frames", false, "write one row per frame instead of one row per game")
+	out := flags.String("o", "", "write to `file` instead of stdout")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errUsage
+	}
+	bowl, err := m.load(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	rows := gameRows(bowl)
+	if *frames {
+		rows = frameRows(bowl)
+	}
+	if *out != "" {
+		return writeCSVFile(*out, rows)
+	}
+	return writeCSV(stdout, rows)
+}
+func (m Model) importCommand(args []string, stdout io.Writer) error {
+	if len(args) != 2 {
+		return errUsage
+	}
+	file, err := os.Open(args[1])
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	archives, rejected := importCSV(file)
+	for _, err := range rejected {
+		fmt.Fprintln(stdout, "Rejected", err)
+	}
+	bowl, err := m.appendArchives(args[0], archives)
+	if err != nil {
+		return err
+	}
+	m.logger.Info(fmt.Sprintf("Import %d games and reject %d rows.", len(archives), len(rejected)))
+	fmt.Fprintf(stdout, "Imported %d games for %s, rejected %d rows.\n", len(archives), bowl.Name, len(rejected))
+	return nil
+}
+func scoreCommand(args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+	game, err := scoring.ParseNotation(strings.Join(args, " "))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(stdout, game.Notation())
+	for i, frame := range game.Frames() {
+		if frame.Scored {
+			fmt.Fprintf(stdout, "%2d %3d\n", i+1, frame.Score)
+		}
+	}
+	fmt.Fprintf(stdout, "Total: %d  Max: %d\n", game.Total(), game.MaxPossible())
+	return nil
+}
+func (m Model) listCommand(args []string, stdout io.Writer) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+	names, err := m.store.Players()
+	if err != nil {
+		return err
+	}
+	failed := 0
+	for _, name := range names {
+		bowl, err := m.load(name)
+		if err != nil {
+			failed++
+			fmt.Fprintf(stdout, "%s: %v\n", name, err)
+			continue
+		}
+		avg := "---"
+		if len(bowl.Archives) > 0 {
+			sum := 0
+			for _, archive := range bowl.Archives {
+				sum += archive.Scores[10]
+			}
+			avg = strconv.Itoa(sum / len(bowl.Archives))
+		}
+		fmt.Fprintf(stdout, "%-37s  Games:%-4d  Avg:%s\n", bowl.Name, len(bowl.Archives), avg)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d players could not be loaded", failed)
+	}
+	return nil
+}
+func (m Model) showCommand(args []string, stdout io.Writer) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	bowl, err := m.load(args[0])
+	if err != nil {
+		return err
+	}
+	m.Bowl = bowl
+	m.printSheet(stdout)
+	return nil
+}
+
+// printSheet prints every archived game of the player and the game in
+// progress on one page.
+func (m Model) printSheet(stdout io.Writer) {
+	fmt.Fprintf(stdout, " Player: %s\n\n", m.Bowl.Name)
+	if len(m.Bowl.Archives) > 0 {
+		m.scoreSel.PerPage = len(m.Bowl.Archives)
+		m.scoreSel.SetTotalPages(len(m.Bowl.Archives))
+		fmt.Fprint(stdout, m.archivesScoreDrawing())
+	}
+	fmt.Fprint(stdout, m.scoreDrawing())
+}
+func (m Model) statsCommand(args []string, stdout io.Writer) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	bowl, err := m.load(args[0])
+	if err != nil {
+		return err
+	}
+	m.Bowl = bowl
+	fmt.Fprintf(stdout, " Player: %s\n\n", m.Bowl.Name)
+	fmt.Fprint(stdout, m.statsScene())
+	return nil
+}
+func (m Model) addCommand(args []string, stdout io.Writer) error {
+	if len(args) < 2 {
+		return errUsage
+	}
+	game, err := scoring.ParseNotation(strings.Join(args[1:], " "))
+	if err != nil {
+		return err
+	}
+	a, err := archiveGame(game, time.Now().Format("2006/01/02 15:04:05 -0700 MST"))
+	if err != nil {
+		return err
+	}
+	bowl, err := m.appendArchives(args[0], []Archive{a})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "Added game %d for %s: %d\n", len(bowl.Archives), bowl.Name, a.Scores[10])
+	return nil
+}
+
+// handicapCommand shows the handicap of a player, after changing how it is
+// worked out when any flag is given.
+func (m Model) handicapCommand(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("handicap", flag.ContinueOnError)
+	basis := flags.Int("basis", scoring.DefaultHandicap.Basis, "take the percentage of the average's distance to `pins`")
+	percent := flags.Int("percent", scoring.DefaultHandicap.Percent, "`percentage` of the distance to the basis")
+	games := flags.Int("games", scoring.DefaultHandicap.Games, "`number` of games that establish an average")
+	round := flags.String("round", string(scoring.DefaultHandicap.Rounding), "round the handicap `down`, up or to the nearest pin")
+	off := flags.Bool("off", false, "bowl without handicap")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errUsage
+	}
+	load := m.load
+	if flags.NFlag() > 0 {
+		load = m.loadClean
+	}
+	bowl, err := load(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	m.Bowl = bowl
+	if flags.NFlag() > 0 {
+		m.Bowl.Handicap = nil
+		if !*off {
+			handicap := scoring.Handicap{Basis: *basis, Percent: *percent, Games: *games, Rounding: scoring.Rounding(*round)}
+			if err := handicap.Validate(); err != nil {
+				return err
+			}
+			m.Bowl.Handicap = &handicap
+		}
+		if err := m.write(); err != nil {
+			return err
+		}
+		m.noteSnapshot("handicap")
+	}
+	if m.Bowl.Handicap == nil {
+		fmt.Fprintf(stdout, "%s bowls without handicap.\n", m.Bowl.Name)
+		return nil
+	}
+	h := m.Bowl.Handicap
+	rounding := h.Rounding
+	if rounding == "" {
+		rounding = scoring.RoundDown
+	}
+	fmt.Fprintf(stdout, "%d%% of %d, rounded %s, after %d games\n", h.Percent, h.Basis, rounding, h.Games)
+	if handicap, ok := m.Bowl.handicap(); ok {
+		fmt.Fprintf(stdout, "Handicap of %s: %d\n", m.Bowl.Name, handicap)
+	} else {
+		fmt.Fprintf(stdout, "%s has no established average yet.\n", m.Bowl.Name)
+	}
+	return nil
+}
+func (m Model) runCommand(args []string) int {
+	m.logger.Info(fmt.Sprintf("Run the \"%s\" command.", args[0]))
+	var err error
+	switch args[0] {
+	case "export":
+		err = m.exportCommand(args[1:], os.Stdout)
+	case "import":
+		err = m.importCommand(args[1:], os.Stdout)
+	case "score":
+		err = scoreCommand(args[1:], os.Stdout)
+	case "list":
+		err = m.listCommand(args[1:], os.Stdout)
+	case "show":
+		err = m.showCommand(args[1:], os.Stdout)
+	case "stats":
+		err = m.statsCommand(args[1:], os.Stdout)
+	case "add":
+		err = m.addCommand(args[1:], os.Stdout)
+	case "serve":
+		err = m.serveCommand(args[1:], os.Stdout)
+	case "migrate":
+		err = m.migrateCommand(args[1:], os.Stdout)
+	case "validate":
+		err = m.validateCommand(args[1:], os.Stdout)
+	case "history":
+		err = m.historyCommand(args[1:], os.Stdout)
+	case "rebuild":
+		err = m.rebuildCommand(args[1:], os.Stdout)
+	case "handicap":
+		err = m.handicapCommand(args[1:], os.Stdout)
+	case "league":
+		err = m.leagueCommand(args[1:], os.Stdout)
+	default:
+		err = errUsage
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestListCommand(t *testing.T) {
+	m := testModel(t)
+	for _, name := range []string{"alice", "carol"} {
+		m.Bowl = initBowl()
+		m.Bowl.Name = name
+		m.Bowl.Archives = []Archive{testArchive(t, "9- 9- 9- 9- 9- 9- 9- 9- 9- 9-", "2024/01/01")}
+		if err := m.write(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writePlayer(t, m, "bob", `{"name":"bob","pins":[`)
+	var out bytes.Buffer
+	if err := m.listCommand(nil, &out); err == nil {
+		t.Error("listCommand() with a broken player = nil error")
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("list printed %d lines, want 3:\n%s", len(lines), out.String())
+	}
+	for i, prefix := range []string{"alice ", "bob: ", "carol "} {
+		if !strings.HasPrefix(lines[i], prefix) {
+			t.Errorf("line %d = %q, want it to start with %q", i+1, lines[i], prefix)
+		}
+	}
+	if !strings.Contains(lines[2], "Games:1") || !strings.Contains(lines[2], "Avg:90") {
+		t.Errorf("carol = %q", lines[2])
+	}
+}
+
+func TestAddCommand(t *testing.T) {
+	m := testModel(t)
+	var out bytes.Buffer
+	if err := m.addCommand([]string{"dave", "X", "X", "X", "X", "X", "X", "X", "X", "X", "XXX"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.addCommand([]string{"dave", "9-", "9-", "9-"}, &out); err == nil {
+		t.Error("addCommand() with an unfinished game = nil error")
+	}
+	bowl, err := m.store.Load("dave")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bowl.Archives) != 1 || bowl.Archives[0].Scores[10] != 300 {
+		t.Errorf("archives = %+v", bowl.Archives)
+	}
+	if out.String() != "Added game 1 for dave: 300\n" {
+		t.Errorf("output = %q", out.String())
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ryokpen87044/bowlingScorer/scoring"
+)
+
+func archiveDate(arc Archive) string {
+	t, err := time.Parse("2006/01/02 15:04:05 -0700 MST", arc.Time)
+	if err != nil {
+		return arc.Time
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
+func disciplineName(name string) string {
+	if name == "" {
+		return "ten-pin"
+	}
+	return name
+}
+func archiveCounts(arc Archive) scoring.Stats {
+	if arc.Discipline != "" {
+		if game, err := arc.box(); err == nil {
+			return scoring.SummarizeBox([]*scoring.BoxGame{game})
+		}
+		return scoring.Stats{}
+	}
+	if game, err := arc.game(); err == nil {
+		return scoring.Summarize([]*scoring.Game{game})
+	}
+	return scoring.Stats{}
+}
+func (a Archive) notation() string {
+	if a.Discipline != "" {
+		return ""
+	}
+	if game, err := a.game(); err == nil {
+		return game.Notation()
+	}
+	return ""
+}
+func gameRows(bowl Bowl) [][]string {
+	rows := [][]string{{"game", "date", "discipline", "total", "strikes", "spares", "opens", "notation"}}
+	for i, arc := range bowl.Archives {
+		st := archiveCounts(arc)
+		rows = append(rows, []string{
+			strconv.Itoa(i + 1),
+			archiveDate(arc),
+			disciplineName(arc.Discipline),
+			strconv.Itoa(arc.Scores[10]),
+			strconv.Itoa(st.Strikes),
+			strconv.Itoa(st.Spares),
+			strconv.Itoa(st.OpenFrames),
+			arc.notation(),
+		})
+	}
+	return rows
+}
+func frameRows(bowl Bowl) [][]string {
+	rows := [][]string{{"game", "date", "frame", "roll1", "roll2", "roll3", "score"}}
+	for i, arc := range bowl.Archives {
+		for f := 0; f < 10; f++ {
+			score := ""
+			if arc.Scores[f+1] != -1 {
+				score = strconv.Itoa(arc.Scores[f+1])
+			}
+			row := []string{strconv.Itoa(i + 1), archiveDate(arc), strconv.Itoa(f + 1), "", "", ""}
+			copy(row[3:], Bowl{Discipline: arc.Discipline, Pins: arc.Pins, Marks: arc.Marks}.frameMarks(f))
+			rows = append(rows, append(row, score))
+		}
+	}
+	return rows
+}
+func writeCSV(w io.Writer, rows [][]string) error {
+	writer := csv.NewWriter(w)
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+func writeCSVFile(path string, rows [][]string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := writeCSV(file, rows); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// export writes the games and the frames of the player to exports/ and
+// returns the paths written.
+func (m Model) export() ([]string, error) {
+	if _, err := os.Stat("exports"); err != nil {
+		if err := os.Mkdir("exports", 0777); err != nil {
+			return nil, fmt.Errorf("create a directory named \"exports\": %w", err)
+		}
+		m.logger.Info("Create a directory named \"exports\".")
+	}
+	games := filepath.Join("exports", fmt.Sprintf("%s.csv", m.Bowl.Name))
+	frames := filepath.Join("exports", fmt.Sprintf("%s-frames.csv", m.Bowl.Name))
+	if err := writeCSVFile(games, gameRows(m.Bowl)); err != nil {
+		return nil, fmt.Errorf("export \"%s\": %w", games, err)
+	}
+	if err := writeCSVFile(frames, frameRows(m.Bowl)); err != nil {
+		return []string{games}, fmt.Errorf("export \"%s\": %w", frames, err)
+	}
+	m.logger.Info(fmt.Sprintf("Export \"%s\" and \"%s\".", games, frames))
+	return []string{games, frames}, nil
+}
+
+// exportCmd exports right away and reports where the files went, or turns a
+// failure into an errorMsg whose retry exports again.
+func (m Model) exportCmd() tea.Cmd {
+	paths, err := m.export()
+	if err != nil {
+		m.logger.Error(err.Error())
+		retry := func() tea.Msg { return m.exportCmd()() }
+		return func() tea.Msg { return errorMsg{err: err, retry: retry} }
+	}
+	return func() tea.Msg { return exportedMsg{paths: paths} }
+}
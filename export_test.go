@@ -0,0 +1,104 @@
+package main
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ryokpen87044/bowlingScorer/scoring"
+)
+
+func TestFrameRows(t *testing.T) {
+	bowl := initBowl()
+	bowl.Archives = []Archive{testArchive(t, "X 9/ 8- X X 7/ 9- X X X9/", "2024/01/01")}
+	box := scoring.NewBox(scoring.FivePin)
+	for _, value := range []int{15, 5, 5, 3, 0} {
+		if err := box.Roll(value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bowl.Archives = append(bowl.Archives, Archive{Time: "2024/01/02", Discipline: scoring.FivePin.Name, Marks: box.Marks(), Scores: box.Scores()})
+	rows := frameRows(bowl)
+	if len(rows) != 21 {
+		t.Fatalf("frameRows() = %d rows, want 21", len(rows))
+	}
+	for _, tt := range []struct {
+		row  int
+		want []string
+	}{
+		{1, []string{"1", "2024/01/01", "1", "X", "", "", "20"}},
+		{2, []string{"1", "2024/01/01", "2", "9", "/", "", "38"}},
+		{10, []string{"1", "2024/01/01", "10", "X", "9", "/", "200"}},
+		{11, []string{"2", "2024/01/02", "1", "X", "", "", "25"}},
+		{12, []string{"2", "2024/01/02", "2", "5", "5", "3", "38"}},
+		{13, []string{"2", "2024/01/02", "3", "-", "", "", ""}},
+	} {
+		if !reflect.DeepEqual(rows[tt.row], tt.want) {
+			t.Errorf("row %d = %q, want %q", tt.row, rows[tt.row], tt.want)
+		}
+	}
+}
+
+func TestExportScene(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	m := testModel(t)
+	m.scene = "mgmtScore"
+	m.Bowl.Name = "alice"
+	m.Bowl.Archives = []Archive{testArchive(t, "X X X X X X X X X XXX", "2024/01/01")}
+	update := func(msg tea.Msg) tea.Cmd {
+		model, cmd := m.Update(msg)
+		m = model.(Model)
+		return cmd
+	}
+
+	// A file in the way of the directory is reported in the error scene.
+	if err := os.WriteFile("exports", nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	keys := tea.KeyMsg{Type: tea.KeyCtrlE}
+	for _, msg := range runCmd(update(keys)) {
+		update(msg)
+	}
+	if m.scene != "errorScene" || !strings.Contains(m.View(), "exports") {
+		t.Fatalf("scene = %s after a failed export:\n%s", m.scene, m.View())
+	}
+
+	os.Remove("exports")
+	for _, msg := range runCmd(m.failure.retry) {
+		update(msg)
+	}
+	if m.scene != "mgmtScore" || m.notice != "Exported exports/alice.csv and exports/alice-frames.csv." {
+		t.Errorf("scene = %s, notice = %q", m.scene, m.notice)
+	}
+	if !strings.Contains(m.View(), "exports/alice.csv") {
+		t.Error("the scene does not show where the export went")
+	}
+	if _, err := os.Stat("exports/alice-frames.csv"); err != nil {
+		t.Error(err)
+	}
+}
+
+// runCmd runs cmd and any batch it returns, and collects their messages.
+func runCmd(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	msg := cmd()
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		var msgs []tea.Msg
+		for _, cmd := range batch {
+			msgs = append(msgs, runCmd(cmd)...)
+		}
+		return msgs
+	}
+	return []tea.Msg{msg}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ryokpen87044/bowlingScorer/scoring"
+)
+
+var dateLayouts = []string{
+	"2006/01/02 15:04:05 -0700 MST",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02",
+}
+
+type rowError struct {
+	line int
+	err  error
+}
+
+func (e rowError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.line, e.err)
+}
+func parseTime(str string) (time.Time, error) {
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, strings.TrimSpace(str), time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", str)
+}
+func parseDate(str string) (string, error) {
+	t, err := parseTime(str)
+	if err != nil {
+		return "", err
+	}
+	return t.Format("2006/01/02 15:04:05 -0700 MST"), nil
+}
+
+// importArchive turns one row, a date followed by the rolls of a complete
+// ten-pin game, into an archive. The scores are always recomputed.
+func importArchive(record []string) (Archive, error) {
+	date, err := parseDate(record[0])
+	if err != nil {
+		return Archive{}, err
+	}
+	game := scoring.New()
+	n := 0
+	for _, roll := range record[1:] {
+		roll = strings.TrimSpace(roll)
+		if roll == "" {
+			continue
+		}
+		n++
+		if err := rollInput(game, roll); err != nil {
+			return Archive{}, fmt.Errorf("%w: %q at roll %d", err, roll, n)
+		}
+	}
+	return archiveGame(game, date)
+}
+
+// archiveGame archives a complete ten-pin game after running it through the
+// same checks as saved data.
+func archiveGame(game *scoring.Game, date string) (Archive, error) {
+	if !game.Complete() {
+		return Archive{}, errors.New("the game is not complete")
+	}
+	a := Archive{
+		Time:    date,
+		Pins:    game.Pins(),
+		Rules:   game.Rules().Name(),
+		Variant: game.Variant().Name,
+		Scores:  game.Scores(),
+	}
+	if _, ok := pinsCheck(a.Pins); !ok {
+		return Archive{}, errors.New("invalid pins")
+	}
+	if _, ok := scoresCheck(a.Scores, 30); !ok {
+		return Archive{}, errors.New("invalid scores")
+	}
+	a.Splits, a.SplitConversions = game.SplitStats()
+	return a, nil
+}
+
+// importCSV reads archives from r. A first row starting with "date" is taken
+// as a header. Rows that cannot be imported are returned as errors with their
+// line numbers and do not stop the rest of the file.
+func importCSV(r io.Reader) ([]Archive, []error) {
+	var archives []Archive
+	var rejected []error
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	for first := true; ; first = false {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				rejected = append(rejected, rowError{line: parseErr.Line, err: parseErr.Err})
+				continue
+			}
+			rejected = append(rejected, err)
+			break
+		}
+		line, _ := reader.FieldPos(0)
+		if first && strings.EqualFold(strings.TrimSpace(record[0]), "date") {
+			continue
+		}
+		a, err := importArchive(record)
+		if err != nil {
+			rejected = append(rejected, rowError{line: line, err: err})
+			continue
+		}
+		archives = append(archives, a)
+	}
+	return archives, rejected
+}
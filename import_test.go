@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const importFile = `date,rolls
+2024-01-01,X,X,X,X,X,X,X,X,X,X,X,X
+2024-01-02 10:00,"9
+",0,9,0,9,0,9,0,9,0,9,0,9,0,9,0,9,0,9,0
+2024-01-03,9,0,9,0
+yesterday,X,X,X,X,X,X,X,X,X,X,X,X
+2024-01-05,X,"X" ,X
+2024-01-06,5,/,5,/,5,/,5,/,5,/,5,/,5,/,5,/,5,/,5,/,5
+2024-01-07,9,2
+`
+
+func TestImportCSV(t *testing.T) {
+	archives, rejected := importCSV(strings.NewReader(importFile))
+	if len(archives) != 3 {
+		t.Fatalf("imported %d games, want 3", len(archives))
+	}
+	for i, want := range []int{300, 90, 150} {
+		if archives[i].Scores[10] != want {
+			t.Errorf("game %d = %d, want %d", i+1, archives[i].Scores[10], want)
+		}
+	}
+	if !strings.HasPrefix(archives[1].Time, "2024/01/02 10:00:00") {
+		t.Errorf("Time = %q", archives[1].Time)
+	}
+	want := []string{
+		"line 5: the game is not complete",
+		`line 6: invalid date "yesterday"`,
+		"line 7: ",
+		"line 9: ",
+	}
+	if len(rejected) != len(want) {
+		t.Fatalf("rejected %d rows, want %d: %v", len(rejected), len(want), rejected)
+	}
+	for i, err := range rejected {
+		if !strings.HasPrefix(err.Error(), want[i]) {
+			t.Errorf("rejected[%d] = %q, want it to start with %q", i, err, want[i])
+		}
+	}
+}
+
+func TestImportCommand(t *testing.T) {
+	m := testModel(t)
+	file := filepath.Join(t.TempDir(), "games.csv")
+	if err := os.WriteFile(file, []byte(importFile), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := m.importCommand([]string{"erin", file}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(out.String(), "Imported 3 games for erin, rejected 4 rows.\n") {
+		t.Errorf("output = %q", out.String())
+	}
+	bowl, err := m.store.Load("erin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bowl.Archives) != 3 {
+		t.Errorf("saved %d games, want 3", len(bowl.Archives))
+	}
+}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// journal keeps every change to a player as one JSON event per line in
+// dir/<name>.jsonl. Events are only ever appended.
+type journal struct {
+	dir string
+}
+
+// sheet is the record of the game in progress, enough to rescore it.
+type sheet struct {
+	Discipline string     `json:"discipline,omitempty"`
+	Pins       [21]string `json:"pins"`
+	Marks      []string   `json:"marks,omitempty"`
+	Leaves     [][]int    `json:"leaves,omitempty"`
+	Rules      string     `json:"rules,omitempty"`
+	Variant    string     `json:"variant,omitempty"`
+}
+
+// journalEvent is one change. Exactly one of Bowl, Sheet, Archived and
+// Archive is set: a whole player, the game in progress after a roll, undo,
+// edit or change of game, the time the game in progress was archived under,
+// or a finished game added from outside. Rolls lists the marks a roll added
+// or an undo took away, for reading the journal; for an edit it holds the
+// new marks of Frame, counting from 1, and Was the marks they replaced.
+type journalEvent struct {
+	Time     time.Time `json:"time"`
+	Kind     string    `json:"kind"`
+	Frame    int       `json:"frame,omitempty"`
+	Was      []string  `json:"was,omitempty"`
+	Rolls    []string  `json:"rolls,omitempty"`
+	Bowl     *Bowl     `json:"bowl,omitempty"`
+	Sheet    *sheet    `json:"sheet,omitempty"`
+	Archived string    `json:"archived,omitempty"`
+	Archive  *Archive  `json:"archive,omitempty"`
+}
+
+func (j journal) path(name string) string {
+	return filepath.Join(j.dir, fmt.Sprintf("%s.jsonl", name))
+}
+func (j journal) exists(name string) bool {
+	_, err := os.Stat(j.path(name))
+	return err == nil
+}
+func (j journal) append(name string, events ...journalEvent) error {
+	if err := os.MkdirAll(j.dir, 0777); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(j.path(name), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(file)
+	for _, e := range events {
+		if err := encoder.Encode(e); err != nil {
+			file.Close()
+			return err
+		}
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+func (j journal) events(name string) ([]journalEvent, error) {
+	file, err := os.Open(j.path(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: no journal for %q", errNoPlayer, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var events []journalEvent
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1<<24)
+	for line := 1; scanner.Scan(); line++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		var e journalEvent
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return events, rowError{line: line, err: err}
+		}
+		events = append(events, e)
+	}
+	return events, scanner.Err()
+}
+func sheetOf(b Bowl) *sheet {
+	return &sheet{
+		Discipline: b.Discipline,
+		Pins:       b.Pins,
+		Marks:      b.Marks,
+		Leaves:     b.Leaves,
+		Rules:      b.Rules,
+		Variant:    b.Variant,
+	}
+}
+func (s sheet) apply(b Bowl) (Bowl, error) {
+	b.Discipline = s.Discipline
+	b.Pins = s.Pins
+	b.Marks = s.Marks
+	b.Leaves = s.Leaves
+	b.Rules = s.Rules
+	b.Variant = s.Variant
+	return b.rescore()
+}
+
+// rolled lists the marks that are in after but not in before, or the other
+// way round for an undo.
+func rolled(before, after Bowl) []string {
+	was, now := before.Pins[:], after.Pins[:]
+	if !after.tenPin() {
+		was, now = before.Marks, after.Marks
+	}
+	if before.Times > after.Times {
+		was, now = now, was
+	}
+	var marks []string
+	for i, mark := range now {
+		if mark != "yet" && (i >= len(was) || was[i] == "yet") {
+			marks = append(marks, mark)
+		}
+	}
+	return marks
+}
+
+// note appends e to the journal of m.Bowl. A player without a journal gets a
+// snapshot of before first, so the journal alone can always rebuild them. A
+// journal that cannot be written is logged rather than stopping the game.
+func (m Model) note(e journalEvent, before Bowl) {
+	if m.journal == nil || m.Bowl.Name == "" {
+		return
+	}
+	e.Time = time.Now()
+	events := []journalEvent{e}
+	if !m.journal.exists(m.Bowl.Name) && e.Bowl == nil {
+		events = append([]journalEvent{{Time: e.Time, Kind: "snapshot", Bowl: &before}}, events...)
+	}
+	if err := m.journal.append(m.Bowl.Name, events...); err != nil {
+		m.logger.Error(fmt.Sprintf("Failed to write the journal of \"%s\": %v", m.Bowl.Name, err))
+	}
+}
+func (m Model) noteGame(kind string, before Bowl) {
+	m.note(journalEvent{Kind: kind, Rolls: rolled(before, m.Bowl), Sheet: sheetOf(m.Bowl)}, before)
+}
+func (m Model) noteEdit(before Bowl) {
+	m.note(journalEvent{
+		Kind:  "edit",
+		Frame: m.editFrame + 1,
+		Was:   before.frameMarks(m.editFrame),
+		Rolls: m.Bowl.frameMarks(m.editFrame),
+		Sheet: sheetOf(m.Bowl),
+	}, before)
+}
+func (m Model) noteNext(before Bowl) {
+	m.note(journalEvent{Kind: "next", Archived: m.Bowl.Archives[len(m.Bowl.Archives)-1].Time}, before)
+}
+func (m Model) noteArchive(a Archive, before Bowl) {
+	m.note(journalEvent{Kind: "archive", Archive: &a}, before)
+}
+func (m Model) noteSnapshot(kind string) {
+	bowl := m.Bowl
+	m.note(journalEvent{Kind: kind, Bowl: &bowl}, bowl)
+}
+
+// rebuild replays events up to and including until, or all of them when
+// until is zero.
+func rebuild(events []journalEvent, until time.Time) (Bowl, error) {
+	var bowl Bowl
+	started := false
+	for i, e := range events {
+		if !until.IsZero() && e.Time.After(until) {
+			break
+		}
+		if !started && e.Bowl == nil {
+			return bowl, fmt.Errorf("event %d: the journal does not start with a snapshot", i+1)
+		}
+		started = true
+		var err error
+		switch {
+		case e.Bowl != nil:
+			bowl = *e.Bowl
+		case e.Sheet != nil:
+			bowl, err = e.Sheet.apply(bowl)
+		case e.Archive != nil:
+			bowl.Archives = append(bowl.Archives, *e.Archive)
+		case e.Kind == "next":
+			bowl, _ = Model{Bowl: bowl, scoreSel: initScoreSel()}.nextGame()
+			bowl.Archives[len(bowl.Archives)-1].Time = e.Archived
+		default:
+			err = fmt.Errorf("unknown event %q", e.Kind)
+		}
+		if err != nil {
+			return bowl, fmt.Errorf("event %d (%s at %s): %w", i+1, e.Kind, e.Time.Format("2006/01/02 15:04:05"), err)
+		}
+	}
+	if !started {
+		return bowl, errors.New("no events by then")
+	}
+	return bowl, nil
+}
+func (m Model) historyCommand(args []string, stdout io.Writer) error {
+	if len(args) != 1 || m.journal == nil {
+		return errUsage
+	}
+	events, err := m.journal.events(args[0])
+	if err != nil {
+		return err
+	}
+	for _, e := range events {
+		detail := strings.Join(e.Rolls, " ")
+		switch {
+		case e.Bowl != nil:
+			detail = fmt.Sprintf("%d games", len(e.Bowl.Archives))
+		case e.Kind == "rules":
+			detail = e.Sheet.Rules
+		case e.Kind == "variant":
+			detail = e.Sheet.Variant
+		case e.Kind == "discipline":
+			detail = disciplineName(e.Sheet.Discipline)
+		case e.Archive != nil:
+			detail = fmt.Sprintf("%s  %d", e.Archive.Time, e.Archive.Scores[10])
+		case e.Kind == "next":
+			detail = e.Archived
+		case e.Frame > 0:
+			detail = fmt.Sprintf("frame %d: %s → %s", e.Frame, strings.Join(e.Was, " "), strings.Join(e.Rolls, " "))
+		}
+		fmt.Fprintf(stdout, "%s  %-10s %s\n", e.Time.Local().Format("2006/01/02 15:04:05"), e.Kind, detail)
+	}
+	return nil
+}
+func (m Model) rebuildCommand(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("rebuild", flag.ContinueOnError)
+	at := flags.String("at", "", "rebuild the sheet as it was at `time`")
+	write := flags.Bool("w", false, "save the rebuilt player over the saved data")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 || m.journal == nil {
+		return errUsage
+	}
+	var until time.Time
+	if *at != "" {
+		var err error
+		if until, err = parseTime(*at); err != nil {
+			return err
+		}
+	}
+	events, err := m.journal.events(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	m.Bowl, err = rebuild(events, until)
+	if err != nil {
+		return err
+	}
+	if *write {
+		if err := m.write(); err != nil {
+			return err
+		}
+		m.noteSnapshot("rebuild")
+		fmt.Fprintf(stdout, "Saved %s as rebuilt from %d events.\n", m.Bowl.Name, len(events))
+		return nil
+	}
+	m.printSheet(stdout)
+	return nil
+}
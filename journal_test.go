@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ryokpen87044/bowlingScorer/scoring"
+)
+
+func rollAll(m Model, notation string) Bowl {
+	return m.addScore(func(game *scoring.Game) error {
+		return rollInput(game, notation)
+	})
+}
+
+func TestRebuild(t *testing.T) {
+	m := testModel(t)
+	m.Bowl.Name = "alice"
+	m.Bowl = rollAll(m, "34 X 5")
+	m.Bowl = m.undoScore()
+	m.editFrame, m.edit = m.moveFrame(-1)
+	m.editFrame, m.edit = m.moveFrame(-1)
+	m.Bowl, m.edit, m.editFrame = m.editScore(func(game *scoring.Game) error { return rollInput(game, "9") })
+	m.Bowl, m.edit, m.editFrame = m.editScore(func(game *scoring.Game) error { return rollInput(game, "/") })
+	m.Bowl = rollAll(m, "X X X X X X X XXX")
+	m.Bowl, m.scoreSel = m.nextGame()
+	m.Bowl = rollAll(m, "9-")
+
+	events, err := m.journal.events("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var kinds []string
+	for _, e := range events {
+		kinds = append(kinds, e.Kind)
+	}
+	if got := strings.Join(kinds, " "); got != "snapshot roll undo edit roll next roll" {
+		t.Errorf("journal = %s", got)
+	}
+	rebuilt, err := rebuild(events, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rebuilt.Archives) != 1 || rebuilt.Archives[0].Scores[10] != m.Bowl.Archives[0].Scores[10] {
+		t.Errorf("rebuilt archives = %+v", rebuilt.Archives)
+	}
+	if rebuilt.Pins != m.Bowl.Pins || rebuilt.Scores != m.Bowl.Scores {
+		t.Errorf("rebuilt game = %v %v, want %v %v", rebuilt.Pins, rebuilt.Scores, m.Bowl.Pins, m.Bowl.Scores)
+	}
+	before, err := rebuild(events, events[2].Time)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if before.Times != 4 {
+		t.Errorf("rebuilt up to the undo has %d rolls, want 4", before.Times)
+	}
+
+	var out bytes.Buffer
+	if err := m.historyCommand([]string{"alice"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"roll       3 4 X 5", "undo       5", "edit       frame 1: 3 4 → 9 /"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("history has no %q:\n%s", want, out.String())
+		}
+	}
+	if _, err := rebuild(events[1:], time.Time{}); err == nil {
+		t.Error("a journal without a snapshot was rebuilt")
+	}
+}
+
+// A roll rejected over HTTP after a finished game must not archive the game,
+// or the journal would hold one more game than the saved data.
+func TestRebuildAfterRejectedRoll(t *testing.T) {
+	m := testModel(t)
+	m.Bowl.Name = "bob"
+	m.Bowl = rollAll(m, "X X X X X X X X X XXX")
+	if err := m.write(); err != nil {
+		t.Fatal(err)
+	}
+	server := &server{m: m}
+	post := func(roll string) int {
+		r := httptest.NewRequest(http.MethodPost, "/players/bob/rolls", strings.NewReader(`{"roll":"`+roll+`"}`))
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, r)
+		return w.Code
+	}
+	if code := post("/"); code != http.StatusUnprocessableEntity {
+		t.Errorf("a spare on a new game = %d, want %d", code, http.StatusUnprocessableEntity)
+	}
+	if code := post("7"); code != http.StatusOK {
+		t.Errorf("a 7 on a new game = %d, want %d", code, http.StatusOK)
+	}
+	saved, err := m.store.Load("bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	events, err := m.journal.events("bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rebuilt, err := rebuild(events, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(saved.Archives) != 1 || len(rebuilt.Archives) != 1 {
+		t.Errorf("saved %d archives and rebuilt %d, want 1", len(saved.Archives), len(rebuilt.Archives))
+	}
+	if saved.Pins[0] != "7" || rebuilt.Pins != saved.Pins {
+		t.Errorf("rebuilt %v, saved %v", rebuilt.Pins, saved.Pins)
+	}
+}
@@ -0,0 +1,901 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ryokpen87044/bowlingScorer/scoring"
+)
+
+var errNoLeague = errors.New("no such league")
+var errScheduled = errors.New("the league already has results; teams and schedule can no longer change")
+
+// League is a weekly league bowled by teams of saved players. Every week
+// each team meets another on a pair of lanes, and every bowler bowls a
+// series of Games games. Matches are scored by Points, or DefaultPoints when
+// it is nil, with Handicap added when it is set.
+type League struct {
+	Name      string `json:"name"`
+	Games     int    `json:"games"`
+	FirstLane int    `json:"firstLane"`
+	Teams     []Team `json:"teams"`
+	Weeks     []Week `json:"weeks"`
+
+	Handicap *scoring.Handicap `json:"handicap,omitempty"`
+	Points   *scoring.Points   `json:"points,omitempty"`
+}
+type Team struct {
+	Name   string   `json:"name"`
+	Roster []string `json:"roster"`
+}
+type Week struct {
+	Date    string  `json:"date,omitempty"`
+	Matches []Match `json:"matches"`
+}
+
+// Match is two teams meeting on a pair of lanes, the home team on the
+// first. A match without Away is a bye for Home and has no lanes.
+type Match struct {
+	Home   string   `json:"home"`
+	Away   string   `json:"away,omitempty"`
+	Lanes  [2]int   `json:"lanes,omitempty"`
+	Series []Series `json:"series,omitempty"`
+}
+
+// Series is the games one bowler bowled for a team in a match, with the
+// handicap they had going into it so results do not move with later
+// averages. A substitute bowls for a team they are not on, and a blind is
+// the series credited to an absent bowler.
+type Series struct {
+	Team     string `json:"team"`
+	Bowler   string `json:"bowler"`
+	Games    []int  `json:"games"`
+	Handicap int    `json:"handicap,omitempty"`
+	Sub      bool   `json:"sub,omitempty"`
+	Blind    bool   `json:"blind,omitempty"`
+}
+
+type leagueMsg struct {
+	league League
+}
+
+// leagueStore keeps one JSON file per league in dir, away from the players
+// so they are never listed as one.
+type leagueStore struct {
+	dir string
+}
+
+func (s leagueStore) path(name string) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%s.json", name))
+}
+func (s leagueStore) Load(name string) (League, error) {
+	var league League
+	file, err := os.Open(s.path(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return league, fmt.Errorf("%w: %q", errNoLeague, name)
+	}
+	if err != nil {
+		return league, err
+	}
+	defer file.Close()
+	if err := json.NewDecoder(file).Decode(&league); err != nil {
+		return league, fmt.Errorf("decode %s: %w", s.path(name), err)
+	}
+	return league, nil
+}
+func (s leagueStore) Save(league League) error {
+	return saveJSON(s.dir, league.Name, league)
+}
+func (s leagueStore) Leagues() ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, path := range paths {
+		names = append(names, strings.TrimSuffix(filepath.Base(path), ".json"))
+	}
+	return names, nil
+}
+
+func (l League) team(name string) (Team, bool) {
+	for _, t := range l.Teams {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return Team{}, false
+}
+func (l League) teamOf(bowler string) (Team, bool) {
+	for _, t := range l.Teams {
+		for _, b := range t.Roster {
+			if b == bowler {
+				return t, true
+			}
+		}
+	}
+	return Team{}, false
+}
+func (l League) points() scoring.Points {
+	if l.Points == nil {
+		return scoring.DefaultPoints
+	}
+	return *l.Points
+}
+func (l League) started() bool {
+	for _, w := range l.Weeks {
+		for _, match := range w.Matches {
+			if len(match.Series) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// setTeam adds a team or replaces the roster of one. New teams can only join
+// before the first results are in, since the schedule is made again for them.
+func (l League) setTeam(name string, roster []string) (League, error) {
+	teams := append([]Team(nil), l.Teams...)
+	for i, t := range teams {
+		if t.Name == name {
+			teams[i].Roster = roster
+			l.Teams = teams
+			return l, nil
+		}
+	}
+	if l.started() {
+		return l, errScheduled
+	}
+	l.Teams = append(teams, Team{Name: name, Roster: roster})
+	l.Weeks = nil
+	return l, nil
+}
+
+// roundRobin pairs every team with every other once by the circle method:
+// the first slot stays put while the others move round one place a week.
+// The pair in the first slot swaps home and away every week and the other
+// pairs take turns by their place in the circle, so home counts differ by at
+// most one. With an odd number of teams an empty slot is the one that stays
+// put, and whoever meets it has a bye.
+func roundRobin(teams []string) [][][2]string {
+	slots := append([]string(nil), teams...)
+	if len(slots)%2 == 1 {
+		slots = append([]string{""}, slots...)
+	}
+	n := len(slots)
+	var weeks [][][2]string
+	for w := 0; w < n-1; w++ {
+		var pairs [][2]string
+		for i := 0; i < n/2; i++ {
+			home, away := slots[i], slots[n-1-i]
+			if i == 0 && w%2 == 1 || i > 0 && i%2 == 1 {
+				home, away = away, home
+			}
+			if home == "" {
+				home, away = away, home
+			}
+			pairs = append(pairs, [2]string{home, away})
+		}
+		weeks = append(weeks, pairs)
+		slots = append([]string{slots[0], slots[n-1]}, slots[1:n-1]...)
+	}
+	return weeks
+}
+
+// schedule makes rounds round robins starting on start, a week apart by
+// every days. Each round swaps home and away, and the pairs of lanes move
+// one along every week so no two teams keep the same pair.
+func (l League) schedule(rounds int, start time.Time, every int) (League, error) {
+	if l.started() {
+		return l, errScheduled
+	}
+	if len(l.Teams) < 2 {
+		return l, fmt.Errorf("league %q needs at least 2 teams", l.Name)
+	}
+	var names []string
+	for _, t := range l.Teams {
+		names = append(names, t.Name)
+	}
+	l.Weeks = nil
+	for round := 0; round < rounds; round++ {
+		for _, pairs := range roundRobin(names) {
+			var week Week
+			if !start.IsZero() {
+				week.Date = start.AddDate(0, 0, every*len(l.Weeks)).Format("2006/01/02")
+			}
+			var byes []Match
+			for _, pair := range pairs {
+				if pair[1] == "" {
+					byes = append(byes, Match{Home: pair[0]})
+					continue
+				}
+				if round%2 == 1 {
+					pair[0], pair[1] = pair[1], pair[0]
+				}
+				week.Matches = append(week.Matches, Match{Home: pair[0], Away: pair[1]})
+			}
+			for i := range week.Matches {
+				lane := l.FirstLane + 2*((i+len(l.Weeks))%len(week.Matches))
+				week.Matches[i].Lanes = [2]int{lane, lane + 1}
+			}
+			week.Matches = append(week.Matches, byes...)
+			l.Weeks = append(l.Weeks, week)
+		}
+	}
+	return l, nil
+}
+
+// enter records a series in a week, replacing one the bowler entered
+// before. The bowler bowls for their own team unless s.Team is given, and is
+// a substitute on any other.
+func (l League) enter(week int, s Series) (League, error) {
+	if week < 1 || week > len(l.Weeks) {
+		return l, fmt.Errorf("league %q has no week %d", l.Name, week)
+	}
+	if len(s.Games) != l.Games {
+		return l, fmt.Errorf("a series is %d games, not %d", l.Games, len(s.Games))
+	}
+	for _, score := range s.Games {
+		if score < 0 || score > 300 {
+			return l, fmt.Errorf("%d is not a possible game", score)
+		}
+	}
+	own, onTeam := l.teamOf(s.Bowler)
+	if s.Team == "" {
+		if !onTeam {
+			return l, fmt.Errorf("%s is on no team of league %q", s.Bowler, l.Name)
+		}
+		s.Team = own.Name
+	}
+	s.Sub = !onTeam || own.Name != s.Team
+	team, bowler := s.Team, s.Bowler
+	weeks := append([]Week(nil), l.Weeks...)
+	matches := append([]Match(nil), weeks[week-1].Matches...)
+	for i, match := range matches {
+		if match.Away == "" || match.Home != team && match.Away != team {
+			continue
+		}
+		var series []Series
+		for _, s := range match.Series {
+			if s.Bowler != bowler {
+				series = append(series, s)
+			}
+		}
+		matches[i].Series = append(series, s)
+		weeks[week-1].Matches = matches
+		l.Weeks = weeks
+		return l, nil
+	}
+	return l, fmt.Errorf("%s bowls no match in week %d", team, week)
+}
+
+// pins is the total a team knocked down in a match, and whether any of its
+// bowlers has bowled.
+func (match Match) pins(team string) (int, bool) {
+	total, bowled := 0, false
+	for _, s := range match.Series {
+		if s.Team != team {
+			continue
+		}
+		bowled = true
+		for _, score := range s.Games {
+			total += score
+		}
+	}
+	return total, bowled
+}
+func (match Match) entries(team string) []scoring.Entry {
+	var entries []scoring.Entry
+	for _, s := range match.Series {
+		if s.Team == team {
+			entries = append(entries, scoring.Entry{Games: s.Games, Handicap: s.Handicap})
+		}
+	}
+	return entries
+}
+
+// matchPoints is what each team of a match has won, and whether both teams have
+// bowled so that there is anything to score.
+func (l League) matchPoints(match Match) (float64, float64, bool) {
+	_, homeBowled := match.pins(match.Home)
+	_, awayBowled := match.pins(match.Away)
+	if match.Away == "" || !homeBowled || !awayBowled {
+		return 0, 0, false
+	}
+	home, away := l.points().Match(match.entries(match.Home), match.entries(match.Away))
+	return home, away, true
+}
+
+// standing is the record of a team over the matches both sides have bowled.
+// A match is won by taking more of its points, and Pins is scratch.
+type standing struct {
+	Team   string
+	Points float64
+	Won    int
+	Lost   int
+	Tied   int
+	Pins   int
+	Games  int
+}
+
+func (l League) standings() []standing {
+	index := map[string]int{}
+	table := make([]standing, len(l.Teams))
+	for i, t := range l.Teams {
+		index[t.Name] = i
+		table[i].Team = t.Name
+	}
+	for _, w := range l.Weeks {
+		for _, match := range w.Matches {
+			home, away, ok := l.matchPoints(match)
+			if !ok {
+				continue
+			}
+			h, a := &table[index[match.Home]], &table[index[match.Away]]
+			h.Points, a.Points = h.Points+home, a.Points+away
+			homePins, _ := match.pins(match.Home)
+			awayPins, _ := match.pins(match.Away)
+			h.Pins, a.Pins = h.Pins+homePins, a.Pins+awayPins
+			h.Games, a.Games = h.Games+l.Games, a.Games+l.Games
+			switch {
+			case home > away:
+				h.Won, a.Lost = h.Won+1, a.Lost+1
+			case home < away:
+				h.Lost, a.Won = h.Lost+1, a.Won+1
+			default:
+				h.Tied, a.Tied = h.Tied+1, a.Tied+1
+			}
+		}
+	}
+	sort.SliceStable(table, func(i, j int) bool {
+		if table[i].Points != table[j].Points {
+			return table[i].Points > table[j].Points
+		}
+		return table[i].Pins > table[j].Pins
+	})
+	return table
+}
+
+// bowlerStanding sums every series a bowler bowled in the league. Blinds
+// are not bowled and are left out.
+type bowlerStanding struct {
+	Bowler     string
+	Games      int
+	Pins       int
+	HighGame   int
+	HighSeries int
+}
+
+func (b bowlerStanding) average() int {
+	if b.Games == 0 {
+		return 0
+	}
+	return b.Pins / b.Games
+}
+func (l League) bowlerStandings() []bowlerStanding {
+	index := map[string]int{}
+	var table []bowlerStanding
+	for _, w := range l.Weeks {
+		for _, match := range w.Matches {
+			for _, s := range match.Series {
+				if s.Blind {
+					continue
+				}
+				i, ok := index[s.Bowler]
+				if !ok {
+					i = len(table)
+					index[s.Bowler] = i
+					table = append(table, bowlerStanding{Bowler: s.Bowler})
+				}
+				series := 0
+				for _, score := range s.Games {
+					series += score
+					if score > table[i].HighGame {
+						table[i].HighGame = score
+					}
+				}
+				table[i].Games += len(s.Games)
+				table[i].Pins += series
+				if series > table[i].HighSeries {
+					table[i].HighSeries = series
+				}
+			}
+		}
+	}
+	sort.SliceStable(table, func(i, j int) bool {
+		return table[i].average() > table[j].average()
+	})
+	return table
+}
+
+// currentWeek is the first week whose matches are not all bowled, or the
+// last week once the league is over.
+func (l League) currentWeek() int {
+	for i, w := range l.Weeks {
+		for _, match := range w.Matches {
+			_, homeBowled := match.pins(match.Home)
+			_, awayBowled := match.pins(match.Away)
+			if match.Away != "" && (!homeBowled || !awayBowled) {
+				return i
+			}
+		}
+	}
+	if len(l.Weeks) == 0 {
+		return 0
+	}
+	return len(l.Weeks) - 1
+}
+
+func (m Model) standingsDrawing() string {
+	standingsDrawing := strings.Builder{}
+	standingsDrawing.WriteString("┏━━━━┳━━━━━━━━━━━━━━━━━━━━┳━━━━━━━┳━━━━━┳━━━━━┳━━━━━┳━━━━━━━┓\n")
+	standingsDrawing.WriteString("┃    ┃ Team               ┃  Pts  ┃  W  ┃  L  ┃  T  ┃  Pins ┃\n")
+	standingsDrawing.WriteString("┣━━━━╋━━━━━━━━━━━━━━━━━━━━╋━━━━━━━╋━━━━━╋━━━━━╋━━━━━╋━━━━━━━┫\n")
+	for i, s := range m.league.standings() {
+		standingsDrawing.WriteString(fmt.Sprintf("┃ %2d ┃ %-18.18s ┃ %5s ┃ %3d ┃ %3d ┃ %3d ┃ %5d ┃\n",
+			i+1, s.Team, strconv.FormatFloat(s.Points, 'f', -1, 64), s.Won, s.Lost, s.Tied, s.Pins))
+	}
+	standingsDrawing.WriteString("┗━━━━┻━━━━━━━━━━━━━━━━━━━━┻━━━━━━━┻━━━━━┻━━━━━┻━━━━━┻━━━━━━━┛\n")
+	bowlers := m.league.bowlerStandings()
+	if len(bowlers) == 0 {
+		return standingsDrawing.String()
+	}
+	standingsDrawing.WriteString("   Bowler               Games  Avg  High  Series\n")
+	for _, b := range bowlers {
+		standingsDrawing.WriteString(fmt.Sprintf("   %-20.20s %5d  %3d  %4d  %6d\n", b.Bowler, b.Games, b.average(), b.HighGame, b.HighSeries))
+	}
+	return standingsDrawing.String()
+}
+func (m Model) weekDrawing(week int) string {
+	weekDrawing := strings.Builder{}
+	w := m.league.Weeks[week]
+	weekDrawing.WriteString(lipgloss.NewStyle().Foreground(docColor).Render(fmt.Sprintf(" Week %-3d %s", week+1, w.Date)))
+	weekDrawing.WriteString("\n")
+	for _, match := range w.Matches {
+		if match.Away == "" {
+			weekDrawing.WriteString(fmt.Sprintf("   Bye          %s\n", match.Home))
+			continue
+		}
+		result := "vs"
+		if home, away, ok := m.league.matchPoints(match); ok {
+			result = fmt.Sprintf("%d (%s) - (%s) %d",
+				scoring.Total(match.entries(match.Home), -1), strconv.FormatFloat(home, 'f', -1, 64),
+				strconv.FormatFloat(away, 'f', -1, 64), scoring.Total(match.entries(match.Away), -1))
+		}
+		weekDrawing.WriteString(fmt.Sprintf("   Lanes %-6s %s  %s  %s\n", fmt.Sprintf("%d-%d", match.Lanes[0], match.Lanes[1]), match.Home, result, match.Away))
+		for _, s := range match.Series {
+			var games []string
+			total := 0
+			for _, score := range s.Games {
+				games = append(games, fmt.Sprintf("%3d", score))
+				total += score
+			}
+			note := ""
+			switch {
+			case s.Blind:
+				note = "  blind"
+			case s.Sub:
+				note = "  sub"
+			}
+			weekDrawing.WriteString(lipgloss.NewStyle().Foreground(docInactiveColor).Render(
+				fmt.Sprintf("     %-14.14s %-20.20s %s  %4d  Hdcp:%-3d%s", s.Team, s.Bowler, strings.Join(games, " "), total, s.Handicap, note)))
+			weekDrawing.WriteString("\n")
+		}
+	}
+	return weekDrawing.String()
+}
+func (m Model) leagueScene() string {
+	leagueScene := strings.Builder{}
+	leagueScene.WriteString(m.standingsDrawing())
+	leagueScene.WriteString("\n")
+	if len(m.league.Weeks) == 0 {
+		leagueScene.WriteString(lipgloss.NewStyle().Foreground(docInactiveColor).Render("   No schedule has been made yet."))
+		leagueScene.WriteString("\n\n")
+		return leagueScene.String()
+	}
+	leagueScene.WriteString(m.weekDrawing(m.week))
+	leagueScene.WriteString("\n")
+	return leagueScene.String()
+}
+func (m Model) leagueSelModeScene() string {
+	leagueSelModeScene := strings.Builder{}
+	leagueSelModeScene.WriteString(fmt.Sprintf("%s\n", m.modeSel.View()))
+	leagueSelModeScene.WriteString(fmt.Sprintf("%s\n", m.leagueSel.View()))
+	return leagueSelModeScene.String()
+}
+func (m Model) initLeagueSel() list.Model {
+	var leagues []list.Item
+	names, err := m.leagues.Leagues()
+	if err != nil {
+		m.logger.Error("Failed to list leagues.")
+	}
+	for _, name := range names {
+		leagues = append(leagues, dish{state: name, desc: "Saved league."})
+	}
+	leagueSel := list.New(leagues, list.NewDefaultDelegate(), 27, 12)
+	leagueSel.Title = "League selection"
+	leagueSel.SetShowTitle(false)
+	leagueSel.SetShowHelp(false)
+	leagueSel.SetShowStatusBar(false)
+	leagueSel.SetFilteringEnabled(false)
+	leagueSel.Styles.NoItems = lipgloss.NewStyle().Foreground(docInactiveColor).PaddingLeft(3)
+	return leagueSel
+}
+func (m Model) leagueCmd(name string) tea.Cmd {
+	return func() tea.Msg {
+		league, err := m.leagues.Load(name)
+		if err != nil {
+			m.logger.Error(err.Error())
+			return errorMsg{err: err, retry: m.leagueCmd(name)}
+		}
+		return leagueMsg{league: league}
+	}
+}
+
+func (m Model) loadLeague(name string) (League, error) {
+	league, err := m.leagues.Load(name)
+	if err != nil {
+		return league, err
+	}
+	m.logger.Info(fmt.Sprintf("Load the league \"%s\".", name))
+	return league, nil
+}
+func (m Model) saveLeague(league League) error {
+	if err := m.leagues.Save(league); err != nil {
+		return fmt.Errorf("save the league \"%s\": %w", league.Name, err)
+	}
+	m.logger.Info(fmt.Sprintf("Save the league \"%s\".", league.Name))
+	return nil
+}
+func (m Model) leagueCommand(args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+	switch args[0] {
+	case "list":
+		return m.leagueListCommand(args[1:], stdout)
+	case "new":
+		return m.leagueNewCommand(args[1:], stdout)
+	case "team":
+		return m.leagueTeamCommand(args[1:], stdout)
+	case "schedule":
+		return m.leagueScheduleCommand(args[1:], stdout)
+	case "score":
+		return m.leagueScoreCommand(args[1:], stdout)
+	case "points":
+		return m.leaguePointsCommand(args[1:], stdout)
+	case "show":
+		return m.leagueShowCommand(args[1:], stdout)
+	}
+	return errUsage
+}
+func (m Model) leagueListCommand(args []string, stdout io.Writer) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+	names, err := m.leagues.Leagues()
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		league, err := m.loadLeague(name)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "%-37s  Teams:%-3d  Weeks:%d\n", league.Name, len(league.Teams), len(league.Weeks))
+	}
+	return nil
+}
+func (m Model) leagueNewCommand(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("league new", flag.ContinueOnError)
+	games := flags.Int("games", 3, "`number` of games in a series")
+	lane := flags.Int("lane", 1, "`number` of the first lane the league bowls on")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errUsage
+	}
+	name := flags.Arg(0)
+	if !validName(name) {
+		return fmt.Errorf("%q cannot be the name of a league", name)
+	}
+	if *games < 1 || *lane < 1 {
+		return errUsage
+	}
+	if _, err := m.leagues.Load(name); !errors.Is(err, errNoLeague) {
+		if err == nil {
+			err = fmt.Errorf("league %q already exists", name)
+		}
+		return err
+	}
+	if err := m.saveLeague(League{Name: name, Games: *games, FirstLane: *lane}); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "Created league %s.\n", name)
+	return nil
+}
+
+// leagueTeamCommand adds a team or replaces its roster. Bowlers who are not
+// saved players yet are saved as new ones.
+func (m Model) leagueTeamCommand(args []string, stdout io.Writer) error {
+	if len(args) < 3 {
+		return errUsage
+	}
+	league, err := m.loadLeague(args[0])
+	if err != nil {
+		return err
+	}
+	var roster []string
+	for _, name := range args[2:] {
+		if m.Bowl, err = m.loadOrCreate(name); err != nil {
+			return err
+		}
+		if _, err := m.store.Load(m.Bowl.Name); errors.Is(err, errNoPlayer) {
+			if err := m.write(); err != nil {
+				return err
+			}
+		}
+		if t, ok := league.teamOf(m.Bowl.Name); ok && t.Name != args[1] {
+			return fmt.Errorf("%s already bowls for %s", m.Bowl.Name, t.Name)
+		}
+		roster = append(roster, m.Bowl.Name)
+	}
+	scheduled := len(league.Weeks) > 0
+	if league, err = league.setTeam(args[1], roster); err != nil {
+		return err
+	}
+	if err := m.saveLeague(league); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "%s: %s\n", args[1], strings.Join(roster, ", "))
+	if scheduled && len(league.Weeks) == 0 {
+		fmt.Fprintln(stdout, "The schedule was cleared for the new team; make it again.")
+	}
+	return nil
+}
+func (m Model) leagueScheduleCommand(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("league schedule", flag.ContinueOnError)
+	rounds := flags.Int("rounds", 1, "`number` of times every team meets every other")
+	start := flags.String("start", "", "`date` of the first week")
+	every := flags.Int("every", 7, "`days` between league nights")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 || *rounds < 1 || *every < 1 {
+		return errUsage
+	}
+	var first time.Time
+	if *start != "" {
+		var err error
+		if first, err = parseTime(*start); err != nil {
+			return err
+		}
+	}
+	league, err := m.loadLeague(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	if league, err = league.schedule(*rounds, first, *every); err != nil {
+		return err
+	}
+	if err := m.saveLeague(league); err != nil {
+		return err
+	}
+	m.league = league
+	for i := range league.Weeks {
+		fmt.Fprint(stdout, m.weekDrawing(i))
+	}
+	return nil
+}
+
+// leagueAverage is the average bowler takes into a week: that of their games
+// in the league before it once there are enough to establish one, or else
+// that of the games saved for the player. It also gives the handicap the
+// average earns, and whether there is an average at all.
+func (m Model) leagueAverage(league League, week int, bowler string) (int, int, bool) {
+	rules := scoring.DefaultHandicap
+	if league.Handicap != nil {
+		rules = *league.Handicap
+	}
+	var totals []int
+	for _, w := range league.Weeks[:week-1] {
+		for _, match := range w.Matches {
+			for _, s := range match.Series {
+				if s.Bowler == bowler && !s.Blind {
+					totals = append(totals, s.Games...)
+				}
+			}
+		}
+	}
+	if len(totals) < rules.Games {
+		totals = nil
+		if bowl, err := m.load(bowler); err == nil {
+			for _, a := range bowl.Archives {
+				if a.Discipline == "" {
+					totals = append(totals, a.Scores[10])
+				}
+			}
+		}
+	}
+	if len(totals) < rules.Games {
+		return 0, 0, false
+	}
+	average := scoring.Average(totals)
+	if league.Handicap == nil {
+		return average, 0, true
+	}
+	return average, rules.Of(average), true
+}
+
+// leagueScoreCommand enters the series of a bowler, or with -blind the blind
+// of an absent one, with the handicap they have going into the week.
+func (m Model) leagueScoreCommand(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("league score", flag.ContinueOnError)
+	team := flags.String("team", "", "enter the series of a substitute for `team`")
+	blind := flags.Bool("blind", false, "enter a blind for an absent bowler instead of games")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() < 3 || *blind != (flags.NArg() == 3) || *blind && *team != "" {
+		return errUsage
+	}
+	league, err := m.loadLeague(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	week, err := strconv.Atoi(flags.Arg(1))
+	if err != nil || week < 1 || week > len(league.Weeks) {
+		return fmt.Errorf("league %q has no week %q", league.Name, flags.Arg(1))
+	}
+	if *team != "" {
+		if _, ok := league.team(*team); !ok {
+			return fmt.Errorf("league %q has no team %q", league.Name, *team)
+		}
+	}
+	series := Series{Team: *team, Bowler: flags.Arg(2), Blind: *blind}
+	average, handicap, established := m.leagueAverage(league, week, series.Bowler)
+	series.Handicap = handicap
+	if *blind {
+		if !established {
+			return fmt.Errorf("%s has no average to take a blind from", series.Bowler)
+		}
+		series.Games = league.points().BlindEntry(average, handicap, league.Games).Games
+	}
+	for _, arg := range flags.Args()[3:] {
+		score, err := strconv.Atoi(arg)
+		if err != nil {
+			return fmt.Errorf("invalid game %q", arg)
+		}
+		series.Games = append(series.Games, score)
+	}
+	if league, err = league.enter(week, series); err != nil {
+		return err
+	}
+	if err := m.saveLeague(league); err != nil {
+		return err
+	}
+	m.league = league
+	fmt.Fprint(stdout, m.weekDrawing(week-1))
+	return nil
+}
+
+// leaguePointsCommand shows how matches of a league are scored, after
+// changing what any flag given sets.
+func (m Model) leaguePointsCommand(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("league points", flag.ContinueOnError)
+	game := flags.Float64("game", scoring.DefaultPoints.Game, "`points` for each game won")
+	series := flags.Float64("series", scoring.DefaultPoints.Series, "`points` for the higher series total")
+	blind := flags.Int("blind", scoring.DefaultPoints.Blind, "`pins` a blind is under the absent bowler's average")
+	basis := flags.Int("basis", scoring.DefaultHandicap.Basis, "take the handicap percentage of the average's distance to `pins`")
+	percent := flags.Int("percent", scoring.DefaultHandicap.Percent, "`percentage` of the distance to the basis")
+	games := flags.Int("games", scoring.DefaultHandicap.Games, "`number` of games that establish an average")
+	round := flags.String("round", string(scoring.DefaultHandicap.Rounding), "round the handicap `down`, up or to the nearest pin")
+	scratch := flags.Bool("scratch", false, "bowl without handicap")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errUsage
+	}
+	league, err := m.loadLeague(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	set := map[string]bool{}
+	flags.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	if len(set) > 0 {
+		points := league.points()
+		if set["game"] {
+			points.Game = *game
+		}
+		if set["series"] {
+			points.Series = *series
+		}
+		if set["blind"] {
+			points.Blind = *blind
+		}
+		if err := points.Validate(); err != nil {
+			return err
+		}
+		league.Points = &points
+		handicap := scoring.DefaultHandicap
+		if league.Handicap != nil {
+			handicap = *league.Handicap
+		}
+		if set["basis"] {
+			handicap.Basis = *basis
+		}
+		if set["percent"] {
+			handicap.Percent = *percent
+		}
+		if set["games"] {
+			handicap.Games = *games
+		}
+		if set["round"] {
+			handicap.Rounding = scoring.Rounding(*round)
+		}
+		if set["basis"] || set["percent"] || set["games"] || set["round"] {
+			if err := handicap.Validate(); err != nil {
+				return err
+			}
+			league.Handicap = &handicap
+		}
+		if *scratch {
+			league.Handicap = nil
+		}
+		if err := m.saveLeague(league); err != nil {
+			return err
+		}
+	}
+	points := league.points()
+	fmt.Fprintf(stdout, "%s points a game won, %s for the series, blinds %d under average\n",
+		strconv.FormatFloat(points.Game, 'f', -1, 64), strconv.FormatFloat(points.Series, 'f', -1, 64), points.Blind)
+	if h := league.Handicap; h != nil {
+		rounding := h.Rounding
+		if rounding == "" {
+			rounding = scoring.RoundDown
+		}
+		fmt.Fprintf(stdout, "Handicap %d%% of %d, rounded %s, after %d games\n", h.Percent, h.Basis, rounding, h.Games)
+	} else {
+		fmt.Fprintln(stdout, "Scratch")
+	}
+	return nil
+}
+func (m Model) leagueShowCommand(args []string, stdout io.Writer) error {
+	if len(args) < 1 || len(args) > 2 {
+		return errUsage
+	}
+	league, err := m.loadLeague(args[0])
+	if err != nil {
+		return err
+	}
+	m.league = league
+	fmt.Fprintf(stdout, " League: %s\n\n", league.Name)
+	fmt.Fprint(stdout, m.standingsDrawing())
+	if len(args) == 1 {
+		for i := range league.Weeks {
+			fmt.Fprint(stdout, "\n", m.weekDrawing(i))
+		}
+		return nil
+	}
+	week, err := strconv.Atoi(args[1])
+	if err != nil || week < 1 || week > len(league.Weeks) {
+		return fmt.Errorf("league %q has no week %q", league.Name, args[1])
+	}
+	fmt.Fprint(stdout, "\n", m.weekDrawing(week-1))
+	return nil
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRoundRobin(t *testing.T) {
+	for _, teams := range [][]string{
+		{"a", "b"},
+		{"a", "b", "c"},
+		{"a", "b", "c", "d"},
+		{"a", "b", "c", "d", "e"},
+		{"a", "b", "c", "d", "e", "f"},
+	} {
+		weeks := roundRobin(teams)
+		met := map[[2]string]int{}
+		home := map[string]int{}
+		for _, pairs := range weeks {
+			bowled := map[string]bool{}
+			for _, pair := range pairs {
+				if pair[0] == "" {
+					t.Errorf("%v: a bye is at home: %v", teams, pairs)
+				}
+				for _, team := range pair {
+					if team != "" && bowled[team] {
+						t.Errorf("%v: %s bowls twice in %v", teams, team, pairs)
+					}
+					bowled[team] = true
+				}
+				if pair[1] == "" {
+					continue
+				}
+				home[pair[0]]++
+				key := pair
+				if key[0] > key[1] {
+					key[0], key[1] = key[1], key[0]
+				}
+				met[key]++
+			}
+		}
+		if want := len(teams) * (len(teams) - 1) / 2; len(met) != want {
+			t.Errorf("%v: %d pairs met, want %d", teams, len(met), want)
+		}
+		for pair, n := range met {
+			if n != 1 {
+				t.Errorf("%v: %v met %d times", teams, pair, n)
+			}
+		}
+		low, high := len(weeks), 0
+		for _, team := range teams {
+			if home[team] < low {
+				low = home[team]
+			}
+			if home[team] > high {
+				high = home[team]
+			}
+		}
+		if high-low > 1 {
+			t.Errorf("%v: home counts %v differ by more than one", teams, home)
+		}
+	}
+}
+
+// testLeague is a scheduled scratch league of teams of one bowler each,
+// named after their team.
+func testLeague(t *testing.T, teams ...string) League {
+	t.Helper()
+	league := League{Name: "tuesday", Games: 3, FirstLane: 5}
+	var err error
+	for _, team := range teams {
+		if league, err = league.setTeam(team, []string{team + "1"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if league, err = league.schedule(2, time.Date(2024, 1, 2, 0, 0, 0, 0, time.Local), 7); err != nil {
+		t.Fatal(err)
+	}
+	return league
+}
+
+func TestSchedule(t *testing.T) {
+	league := testLeague(t, "a", "b", "c")
+	if len(league.Weeks) != 6 {
+		t.Fatalf("scheduled %d weeks, want 6", len(league.Weeks))
+	}
+	if league.Weeks[1].Date != "2024/01/09" {
+		t.Errorf("week 2 is on %s", league.Weeks[1].Date)
+	}
+	for i, w := range league.Weeks {
+		if len(w.Matches) != 2 || w.Matches[0].Lanes != [2]int{5, 6} || w.Matches[1].Away != "" || w.Matches[1].Lanes != [2]int{} {
+			t.Errorf("week %d = %+v, want a match on lanes 5 and 6 and a bye", i+1, w.Matches)
+		}
+	}
+	for i := 0; i < 3; i++ {
+		first, second := league.Weeks[i].Matches[0], league.Weeks[i+3].Matches[0]
+		if first.Home != second.Away || first.Away != second.Home {
+			t.Errorf("week %d has %s at %s, week %d %s at %s", i+1, first.Away, first.Home, i+4, second.Away, second.Home)
+		}
+	}
+
+	league = testLeague(t, "a", "b", "c", "d")
+	var lanes [][2]int
+	for _, w := range league.Weeks[:2] {
+		for _, match := range w.Matches {
+			lanes = append(lanes, match.Lanes)
+		}
+	}
+	if want := [][2]int{{5, 6}, {7, 8}, {7, 8}, {5, 6}}; !reflect.DeepEqual(lanes, want) {
+		t.Errorf("lanes of the first two weeks = %v, want %v", lanes, want)
+	}
+
+	league, err := league.enter(1, Series{Bowler: "a1", Games: []int{150, 150, 150}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := league.schedule(1, time.Time{}, 7); err != errScheduled {
+		t.Errorf("schedule() after results = %v, want %v", err, errScheduled)
+	}
+	if _, err := league.setTeam("e", []string{"e1"}); err != errScheduled {
+		t.Errorf("setTeam() of a new team after results = %v, want %v", err, errScheduled)
+	}
+	if _, err := league.setTeam("a", []string{"a1", "a2"}); err != nil {
+		t.Errorf("setTeam() of a roster after results = %v", err)
+	}
+}
+
+func TestEnter(t *testing.T) {
+	league := testLeague(t, "a", "b")
+	for _, tt := range []struct {
+		week   int
+		series Series
+	}{
+		{0, Series{Bowler: "a1", Games: []int{100, 100, 100}}},
+		{3, Series{Bowler: "a1", Games: []int{100, 100, 100}}},
+		{1, Series{Bowler: "a1", Games: []int{100, 100}}},
+		{1, Series{Bowler: "a1", Games: []int{100, 100, 301}}},
+		{1, Series{Bowler: "x1", Games: []int{100, 100, 100}}},
+	} {
+		if _, err := league.enter(tt.week, tt.series); err == nil {
+			t.Errorf("enter(%d, %+v) = nil error", tt.week, tt.series)
+		}
+	}
+
+	var err error
+	for _, s := range []Series{
+		{Bowler: "a1", Games: []int{100, 100, 100}},
+		{Bowler: "a1", Games: []int{200, 160, 100}},
+		{Bowler: "b1", Games: []int{150, 150, 150}},
+	} {
+		if league, err = league.enter(1, s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	series := league.Weeks[0].Matches[0].Series
+	if len(series) != 2 || series[0].Bowler != "a1" || series[0].Team != "a" || series[0].Games[0] != 200 {
+		t.Errorf("series = %+v, want the second series of a1 in place of the first", series)
+	}
+	if league.currentWeek() != 1 {
+		t.Errorf("currentWeek() = %d, want 1", league.currentWeek())
+	}
+
+	table := league.standings()
+	want := []standing{
+		{Team: "a", Points: 6, Won: 1, Pins: 460, Games: 3},
+		{Team: "b", Points: 2, Lost: 1, Pins: 450, Games: 3},
+	}
+	if !reflect.DeepEqual(table, want) {
+		t.Errorf("standings() = %+v, want %+v", table, want)
+	}
+	bowlers := league.bowlerStandings()
+	if len(bowlers) != 2 || bowlers[0].Bowler != "a1" || bowlers[0].HighGame != 200 || bowlers[0].HighSeries != 460 || bowlers[0].average() != 153 {
+		t.Errorf("bowlerStandings() = %+v", bowlers)
+	}
+}
+
+func TestLeagueScore(t *testing.T) {
+	m := testModel(t)
+	for _, name := range []string{"a1", "b1"} {
+		m.Bowl = initBowl()
+		m.Bowl.Name = name
+		for i := 0; i < 3; i++ {
+			m.Bowl.Archives = append(m.Bowl.Archives, testArchive(t, "5/ 5/ 5/ 5/ 5/ 5/ 5/ 5/ 5/ 5/5", "2024/01/01"))
+		}
+		if err := m.write(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var out bytes.Buffer
+	for _, args := range [][]string{
+		{"new", "tuesday"},
+		{"team", "tuesday", "a", "a1"},
+		{"team", "tuesday", "b", "b1"},
+		{"schedule", "tuesday"},
+		{"points", "-blind", "10", "-basis", "200", "-percent", "100", "tuesday"},
+		{"score", "tuesday", "1", "a1", "150", "150", "150"},
+		{"score", "-blind", "tuesday", "1", "b1"},
+		{"score", "-team", "b", "tuesday", "1", "c1", "20", "20", "20"},
+	} {
+		out.Reset()
+		if err := m.leagueCommand(args, &out); err != nil {
+			t.Fatalf("league %s: %v", strings.Join(args, " "), err)
+		}
+	}
+	if err := m.leagueCommand([]string{"score", "-blind", "tuesday", "1", "c1"}, &out); err == nil {
+		t.Error("a blind for a bowler without an average = nil error")
+	}
+
+	league, err := m.loadLeague("tuesday")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Series{
+		{Team: "a", Bowler: "a1", Games: []int{150, 150, 150}, Handicap: 50},
+		{Team: "b", Bowler: "b1", Games: []int{140, 140, 140}, Handicap: 50, Blind: true},
+		{Team: "b", Bowler: "c1", Games: []int{20, 20, 20}, Sub: true},
+	}
+	match := league.Weeks[0].Matches[0]
+	if !reflect.DeepEqual(match.Series, want) {
+		t.Errorf("series = %+v, want %+v", match.Series, want)
+	}
+	if home, away, ok := league.matchPoints(match); !ok || home != 0 || away != 8 {
+		t.Errorf("matchPoints() = %g, %g, %t, want 0, 8, true", home, away, ok)
+	}
+	for _, b := range league.bowlerStandings() {
+		if b.Bowler == "b1" {
+			t.Errorf("bowlerStandings() lists the blind of b1: %+v", b)
+		}
+	}
+
+	out.Reset()
+	if err := m.leagueCommand([]string{"points", "-scratch", "-game", "1", "tuesday"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if want := "1 points a game won, 2 for the series, blinds 10 under average\nScratch\n"; out.String() != want {
+		t.Errorf("points = %q, want %q", out.String(), want)
+	}
+	if league, err = m.loadLeague("tuesday"); err != nil {
+		t.Fatal(err)
+	}
+	if home, away, _ := league.matchPoints(league.Weeks[0].Matches[0]); home != 0 || away != 5 {
+		t.Errorf("matchPoints() after a change of points = %g, %g, want 0, 5", home, away)
+	}
+}
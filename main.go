@@ -1,13 +1,18 @@
 package main
 
 import (
-	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/charmbracelet/bubbles/filepicker"
@@ -19,11 +24,15 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/log"
+	"github.com/ryokpen87044/bowlingScorer/scoring"
 )
 
 var docStyle = lipgloss.NewStyle().Margin(1, 0)
 var docColor = lipgloss.Color("#EE6FF8")
 var docInactiveColor = lipgloss.Color("#626262")
+var splitStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF5F87"))
+var noTapStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#5FAFFF"))
+var freeStyle = lipgloss.NewStyle().Foreground(docInactiveColor)
 
 type Model struct {
 	Bowl Bowl
@@ -39,19 +48,88 @@ type Model struct {
 	dataSel    filepicker.Model
 	scoreInput textinput.Model
 	scoreSel   paginator.Model
+	editKeys   editKeyMap
+	gameKeys   gameKeyMap
+	editFrame  int
+	edit       Bowl
+	deckMode   bool
+	deck       scoring.Deck
+	laneInput  textinput.Model
+	lane       []Bowl
+	laneIssues [][]finding
+	turn       int
+	lastTurn   int
+	handback   int
+	events     *broker
+	store      Storage
+	playerSel  list.Model
+	findings   []finding
+	repairKeys repairKeyMap
+	failure    errorMsg
+	resume     string
+	errorKeys  errorKeyMap
+	journal    *journal
+	leagues    leagueStore
+	leagueSel  list.Model
+	league     League
+	week       int
+	notice     string
 }
 type Bowl struct {
-	Name     string     `json:"name"`
-	Pins     [21]string `json:"pins"`
-	Scores   [11]int    `json:"scores"`
-	MaxScore int        `json:"maxScore"`
-	Times    int        `json:"times"`
-	Archives []Archive  `json:"archives"`
+	SchemaVersion int `json:"schemaVersion"`
+
+	Name       string     `json:"name"`
+	Discipline string     `json:"discipline,omitempty"`
+	Pins       [21]string `json:"pins"`
+	Marks      []string   `json:"marks,omitempty"`
+	Leaves     [][]int    `json:"leaves,omitempty"`
+	Rules      string     `json:"rules,omitempty"`
+	Variant    string     `json:"variant,omitempty"`
+	Scores     [11]int    `json:"scores"`
+	MaxScore   int        `json:"maxScore"`
+	Times      int        `json:"times"`
+	Archives   []Archive  `json:"archives"`
+
+	Handicap *scoring.Handicap `json:"handicap,omitempty"`
 }
 type Archive struct {
-	Time   string     `json:"time"`
-	Pins   [21]string `json:"pins"`
-	Scores [11]int    `json:"scores"`
+	Time       string     `json:"time"`
+	Discipline string     `json:"discipline,omitempty"`
+	Pins       [21]string `json:"pins"`
+	Marks      []string   `json:"marks,omitempty"`
+	Leaves     [][]int    `json:"leaves,omitempty"`
+	Rules      string     `json:"rules,omitempty"`
+	Variant    string     `json:"variant,omitempty"`
+	Scores     [11]int    `json:"scores"`
+	Handicap   int        `json:"handicap,omitempty"`
+
+	Splits           int `json:"splits"`
+	SplitConversions int `json:"splitConversions"`
+}
+
+type signalMsg struct {
+	sig os.Signal
+}
+
+// errorMsg reports a failed load or save. retry runs the same operation
+// again; it is nil when there is nothing to retry.
+type errorMsg struct {
+	err   error
+	retry tea.Cmd
+}
+type loadMsg struct {
+	bowl     Bowl
+	findings []finding
+}
+type laneMsg struct {
+	lane     []Bowl
+	findings [][]finding
+}
+type savedMsg struct {
+	quit bool
+}
+type exportedMsg struct {
+	paths []string
 }
 
 type inputKeyMap struct {
@@ -64,6 +142,31 @@ type selectKeyMap struct {
 	prev  key.Binding
 	quit  key.Binding
 }
+type editKeyMap struct {
+	undo key.Binding
+	up   key.Binding
+	down key.Binding
+	deck key.Binding
+}
+type gameKeyMap struct {
+	stats      key.Binding
+	rules      key.Binding
+	variant    key.Binding
+	discipline key.Binding
+	export     key.Binding
+}
+type repairKeyMap struct {
+	keep    key.Binding
+	repair  key.Binding
+	discard key.Binding
+	quit    key.Binding
+}
+type errorKeyMap struct {
+	retry key.Binding
+	other key.Binding
+	menu  key.Binding
+	quit  key.Binding
+}
 
 var inputKeys = inputKeyMap{
 	enter: key.NewBinding(
@@ -111,6 +214,82 @@ var rightLeftKeys = selectKeyMap{
 		key.WithHelp("q", "quit"),
 	),
 }
+var editKeys = editKeyMap{
+	undo: key.NewBinding(
+		key.WithKeys("ctrl+z"),
+		key.WithHelp("^z", "undo"),
+	),
+	up: key.NewBinding(
+		key.WithKeys("up"),
+		key.WithHelp("↑", "prev frame"),
+	),
+	down: key.NewBinding(
+		key.WithKeys("down"),
+		key.WithHelp("↓", "next frame"),
+	),
+	deck: key.NewBinding(
+		key.WithKeys("tab"),
+		key.WithHelp("⇥", "pin deck"),
+	),
+}
+var gameKeys = gameKeyMap{
+	stats: key.NewBinding(
+		key.WithKeys("ctrl+s"),
+		key.WithHelp("^s", "stats"),
+	),
+	rules: key.NewBinding(
+		key.WithKeys("ctrl+r"),
+		key.WithHelp("^r", "rules"),
+	),
+	variant: key.NewBinding(
+		key.WithKeys("ctrl+g"),
+		key.WithHelp("^g", "variant"),
+	),
+	discipline: key.NewBinding(
+		key.WithKeys("ctrl+t"),
+		key.WithHelp("^t", "discipline"),
+	),
+	export: key.NewBinding(
+		key.WithKeys("ctrl+e"),
+		key.WithHelp("^e", "export"),
+	),
+}
+var repairKeys = repairKeyMap{
+	keep: key.NewBinding(
+		key.WithKeys("k"),
+		key.WithHelp("k", "keep"),
+	),
+	repair: key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "repair"),
+	),
+	discard: key.NewBinding(
+		key.WithKeys("d"),
+		key.WithHelp("d", "discard"),
+	),
+	quit: key.NewBinding(
+		key.WithKeys("q", "esc", "ctrl+c"),
+		key.WithHelp("q", "quit"),
+	),
+}
+var errorKeys = errorKeyMap{
+	retry: key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "retry"),
+	),
+	other: key.NewBinding(
+		key.WithKeys("o"),
+		key.WithHelp("o", "other file"),
+	),
+	menu: key.NewBinding(
+		key.WithKeys("m"),
+		key.WithHelp("m", "menu"),
+	),
+	quit: key.NewBinding(
+		key.WithKeys("q", "esc", "ctrl+c"),
+		key.WithHelp("q", "quit"),
+	),
+}
 
 func (k inputKeyMap) ShortHelp() []key.Binding {
 	return []key.Binding{k.enter, k.quit}
@@ -118,12 +297,36 @@ func (k inputKeyMap) ShortHelp() []key.Binding {
 func (k selectKeyMap) ShortHelp() []key.Binding {
 	return []key.Binding{k.next, k.prev, k.enter, k.quit}
 }
+func (k editKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.up, k.down, k.undo, k.deck}
+}
+func (k gameKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.stats, k.rules, k.variant, k.discipline, k.export}
+}
+func (k repairKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.keep, k.repair, k.discard, k.quit}
+}
+func (k errorKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.retry, k.other, k.menu, k.quit}
+}
 func (k inputKeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{{}, {}}
 }
 func (k selectKeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{{}, {}}
 }
+func (k editKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{{}, {}}
+}
+func (k gameKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{{}, {}}
+}
+func (k repairKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{{}, {}}
+}
+func (k errorKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{{}, {}}
+}
 
 type dish struct {
 	state string
@@ -133,6 +336,8 @@ type dish struct {
 var menu = []list.Item{
 	dish{state: "new user", desc: "Create new data."},
 	dish{state: "existing user", desc: "Select saved data."},
+	dish{state: "lane", desc: "Bowl with several players."},
+	dish{state: "league", desc: "Browse league standings."},
 }
 
 func (d dish) Title() string       { return d.state }
@@ -141,303 +346,473 @@ func (d dish) FilterValue() string { return d.state }
 
 func (m Model) Init() tea.Cmd {
 	m.logger.Info("Launch the app.")
+	if m.failure.err != nil {
+		failure := m.failure
+		return tea.Batch(m.dataSel.Init(), func() tea.Msg { return failure })
+	}
 	return m.dataSel.Init()
 }
 
-func (m Model) strike(pin string) ([21]string, int) {
-	if n, err := strconv.Atoi(pin); err == nil {
-		if n < 10 {
-			if n == 0 {
-				m.Bowl.Pins[m.Bowl.Times] = "G"
-			} else {
-				m.Bowl.Pins[m.Bowl.Times] = pin
-			}
-			m.Bowl.Times += 1
-		} else if n == 10 {
-			m.Bowl.Pins[m.Bowl.Times] = "X"
-			m.Bowl.Times += 2
+func rollInput(game *scoring.Game, str string) error {
+	if n, err := strconv.Atoi(str); err == nil {
+		return game.Roll(n)
+	}
+	if len(strings.TrimSpace(str)) > 1 {
+		return game.RollNotation(str)
+	}
+	switch {
+	case regexp.MustCompile(`^[xX]$`).MatchString(str):
+		if !game.FullRack() {
+			return scoring.ErrInvalidRoll
 		}
-	} else {
-		if regexp.MustCompile(`^[xX]$`).MatchString(pin) {
-			m.Bowl.Pins[m.Bowl.Times] = "X"
-			m.Bowl.Times += 2
-		} else if regexp.MustCompile(`^[gG]$`).MatchString(pin) {
-			m.Bowl.Pins[m.Bowl.Times] = "G"
-			m.Bowl.Times += 1
-		} else if regexp.MustCompile(`^-$`).MatchString(pin) {
-			m.Bowl.Pins[m.Bowl.Times] = "G"
-			m.Bowl.Times += 1
-		} else if regexp.MustCompile(`^[fF]$`).MatchString(pin) {
-			m.Bowl.Pins[m.Bowl.Times] = "F"
-			m.Bowl.Times += 1
+		return game.Roll(scoring.NumPins)
+	case regexp.MustCompile(`^/$`).MatchString(str):
+		if game.FullRack() {
+			return scoring.ErrInvalidRoll
 		}
+		return game.Roll(game.Standing())
+	case regexp.MustCompile(`^[gG-]$`).MatchString(str):
+		return game.Roll(0)
+	case regexp.MustCompile(`^[fF]$`).MatchString(str):
+		return game.Foul()
 	}
-	return m.Bowl.Pins, m.Bowl.Times
+	return scoring.ErrInvalidRoll
 }
-func (m Model) spare(pin string) ([21]string, int) {
-	if n, err := strconv.Atoi(pin); err == nil {
-		if l, e := strconv.Atoi(m.Bowl.Pins[m.Bowl.Times-1]); e == nil {
-			if n < 10-l {
-				if n == 0 {
-					m.Bowl.Pins[m.Bowl.Times] = "-"
-				} else {
-					m.Bowl.Pins[m.Bowl.Times] = pin
-				}
-				m.Bowl.Times += 1
-			} else if n == 10-l {
-				m.Bowl.Pins[m.Bowl.Times] = "/"
-				m.Bowl.Times += 1
-			}
-		} else {
-			if n < 10 {
-				if n == 0 {
-					m.Bowl.Pins[m.Bowl.Times] = "-"
-				} else {
-					m.Bowl.Pins[m.Bowl.Times] = pin
-				}
-				m.Bowl.Times += 1
-			} else if n == 10 {
-				m.Bowl.Pins[m.Bowl.Times] = "/"
-				m.Bowl.Times += 1
-			}
+func (m Model) input() func(*scoring.Game) error {
+	if m.deckMode {
+		deck := m.deck
+		return func(game *scoring.Game) error {
+			return game.RollLeave(deck)
 		}
-	} else {
-		if regexp.MustCompile(`^/$`).MatchString(pin) {
-			m.Bowl.Pins[m.Bowl.Times] = "/"
-			m.Bowl.Times += 1
-		} else if regexp.MustCompile(`^[gG]$`).MatchString(pin) {
-			m.Bowl.Pins[m.Bowl.Times] = "-"
-			m.Bowl.Times += 1
-		} else if regexp.MustCompile(`^-$`).MatchString(pin) {
-			m.Bowl.Pins[m.Bowl.Times] = "-"
-			m.Bowl.Times += 1
-		} else if regexp.MustCompile(`^[fF]$`).MatchString(pin) {
-			m.Bowl.Pins[m.Bowl.Times] = "F"
-			m.Bowl.Times += 1
+	}
+	str := m.scoreInput.Value()
+	return func(game *scoring.Game) error {
+		return rollInput(game, str)
+	}
+}
+func newGame(pins [21]string, leaves [][]int, rulesName string, variantName string) (*scoring.Game, error) {
+	rules, err := scoring.LookupRules(rulesName)
+	if err != nil {
+		return nil, err
+	}
+	variant, err := scoring.LookupVariant(variantName)
+	if err != nil {
+		return nil, err
+	}
+	return scoring.FromRecord(pins, leaves, scoring.WithRules(rules), scoring.WithVariant(variant))
+}
+func (b Bowl) game() (*scoring.Game, error) {
+	return newGame(b.Pins, b.Leaves, b.Rules, b.Variant)
+}
+func (a Archive) game() (*scoring.Game, error) {
+	return newGame(a.Pins, a.Leaves, a.Rules, a.Variant)
+}
+func slotsOf(game *scoring.Game, err error) ([21]bool, [21]bool) {
+	if err != nil {
+		return [21]bool{}, [21]bool{}
+	}
+	return game.Splits(), game.FreeSlots()
+}
+func record(game *scoring.Game) Bowl {
+	return Bowl{
+		Pins:    game.Pins(),
+		Leaves:  game.Leaves(),
+		Rules:   game.Rules().Name(),
+		Variant: game.Variant().Name,
+	}
+}
+func (m Model) addScore(roll func(*scoring.Game) error) Bowl {
+	game, err := m.Bowl.game()
+	if err != nil {
+		m.logger.Error(err.Error())
+		return m.Bowl
+	}
+	if err := roll(game); err != nil {
+		return m.Bowl
+	}
+	before := m.Bowl
+	m.Bowl = m.applyGame(game)
+	m.publish("roll", before)
+	m.noteGame("roll", before)
+	return m.Bowl
+}
+func (m Model) applyGame(game *scoring.Game) Bowl {
+	m.Bowl.Pins = game.Pins()
+	m.Bowl.Leaves = game.Leaves()
+	m.Bowl.Rules = game.Rules().Name()
+	m.Bowl.Variant = game.Variant().Name
+	m.Bowl.Scores = game.Scores()
+	m.Bowl.MaxScore = game.MaxPossible()
+	m.Bowl.Times = game.Times()
+	return m.Bowl
+}
+func boxRollInput(game *scoring.BoxGame, str string) error {
+	if n, err := strconv.Atoi(str); err == nil {
+		return game.Roll(n)
+	}
+	switch {
+	case regexp.MustCompile(`^[xX]$`).MatchString(str):
+		if !game.FullRack() {
+			return scoring.ErrInvalidRoll
+		}
+		return game.Roll(game.Standing())
+	case regexp.MustCompile(`^/$`).MatchString(str):
+		if game.FullRack() {
+			return scoring.ErrInvalidRoll
 		}
+		return game.Roll(game.Standing())
+	case regexp.MustCompile(`^[gG-]$`).MatchString(str):
+		return game.Roll(0)
 	}
-	return m.Bowl.Pins, m.Bowl.Times
+	return scoring.ErrInvalidRoll
 }
-func (m Model) score() [11]int {
-	frameNumber := 0
-	for i, pin := range m.Bowl.Pins {
-		if i < 18 {
-			if i%2 == 0 {
-				frameNumber++
-			}
-			if p, err := strconv.Atoi(pin); err == nil {
-				if i%2 != 0 {
-					m.Bowl.Scores[frameNumber] = m.Bowl.Scores[frameNumber-1]
-					if q, err := strconv.Atoi(m.Bowl.Pins[i-1]); err == nil {
-						m.Bowl.Scores[frameNumber] += p + q
-					} else {
-						m.Bowl.Scores[frameNumber] += p
-					}
-				}
-			} else {
-				switch pin {
-				case "X":
-					if m.Bowl.Pins[i+3] != "yet" || m.Bowl.Pins[i+4] != "yet" {
-						m.Bowl.Scores[frameNumber] = m.Bowl.Scores[frameNumber-1]
-						m.Bowl.Scores[frameNumber] += 10
-						if p, e := strconv.Atoi(m.Bowl.Pins[i+2]); e == nil {
-							m.Bowl.Scores[frameNumber] += p
-							if m.Bowl.Pins[i+3] == "/" {
-								m.Bowl.Scores[frameNumber] += 10 - p
-							} else {
-								if p, e := strconv.Atoi(m.Bowl.Pins[i+3]); e == nil {
-									m.Bowl.Scores[frameNumber] += p
-								} else {
-									m.Bowl.Scores[frameNumber] += 0
-								}
-							}
-						} else if m.Bowl.Pins[i+2] == "X" {
-							m.Bowl.Scores[frameNumber] += 10
-							if frameNumber == 9 {
-								if p, e := strconv.Atoi(m.Bowl.Pins[i+3]); e == nil {
-									m.Bowl.Scores[frameNumber] += p
-								} else if m.Bowl.Pins[i+3] == "X" {
-									m.Bowl.Scores[frameNumber] += 10
-								} else {
-									m.Bowl.Scores[frameNumber] += 0
-								}
-							} else {
-								if p, e := strconv.Atoi(m.Bowl.Pins[i+4]); e == nil {
-									m.Bowl.Scores[frameNumber] += p
-								} else if m.Bowl.Pins[i+4] == "X" {
-									m.Bowl.Scores[frameNumber] += 10
-								} else {
-									m.Bowl.Scores[frameNumber] += 0
-								}
-							}
-						} else {
-							m.Bowl.Scores[frameNumber] += 0
-							if m.Bowl.Pins[i+3] == "/" {
-								m.Bowl.Scores[frameNumber] += 10
-							} else {
-								if p, e := strconv.Atoi(m.Bowl.Pins[i+3]); e == nil {
-									m.Bowl.Scores[frameNumber] += p
-								} else {
-									m.Bowl.Scores[frameNumber] += 0
-								}
-							}
-						}
-					}
-				case "/":
-					if m.Bowl.Pins[i+1] != "yet" {
-						m.Bowl.Scores[frameNumber] = m.Bowl.Scores[frameNumber-1]
-						m.Bowl.Scores[frameNumber] += 10
-						if p, e := strconv.Atoi(m.Bowl.Pins[i+1]); e == nil {
-							m.Bowl.Scores[frameNumber] += p
-						} else if m.Bowl.Pins[i+1] == "X" {
-							m.Bowl.Scores[frameNumber] += 10
-						} else {
-							m.Bowl.Scores[frameNumber] += 0
-						}
-					}
-				default:
-					if regexp.MustCompile(`^[-GF]$`).MatchString(pin) {
-						if i%2 != 0 {
-							m.Bowl.Scores[frameNumber] = m.Bowl.Scores[frameNumber-1]
-							if p, e := strconv.Atoi(m.Bowl.Pins[i-1]); e == nil {
-								m.Bowl.Scores[frameNumber] += p
-							} else {
-								m.Bowl.Scores[frameNumber] += 0
-							}
-						}
-					}
-				}
-			}
-		} else {
-			if i == 18 {
-				frameNumber++
-			}
-			if m.Bowl.Times == 21 {
-				m.Bowl.Scores[frameNumber] = m.Bowl.Scores[frameNumber-1]
-				if p, err := strconv.Atoi(m.Bowl.Pins[18]); err == nil {
-					if m.Bowl.Pins[19] != "/" {
-						m.Bowl.Scores[frameNumber] += p
-					}
-				} else {
-					switch m.Bowl.Pins[18] {
-					case "X":
-						m.Bowl.Scores[frameNumber] += 10
-					default:
-						m.Bowl.Scores[frameNumber] += 0
-					}
-				}
-				if p, err := strconv.Atoi(m.Bowl.Pins[19]); err == nil {
-					if m.Bowl.Pins[20] != "/" {
-						m.Bowl.Scores[frameNumber] += p
-					}
-				} else {
-					switch m.Bowl.Pins[19] {
-					case "X":
-						m.Bowl.Scores[frameNumber] += 10
-					case "/":
-						m.Bowl.Scores[frameNumber] += 10
-					default:
-						m.Bowl.Scores[frameNumber] += 0
-					}
-				}
-				if p, err := strconv.Atoi(m.Bowl.Pins[20]); err == nil {
-					m.Bowl.Scores[frameNumber] += p
-				} else {
-					switch m.Bowl.Pins[20] {
-					case "X":
-						m.Bowl.Scores[frameNumber] += 10
-					case "/":
-						m.Bowl.Scores[frameNumber] += 10
-					default:
-						m.Bowl.Scores[frameNumber] += 0
-					}
-				}
-			}
+func newBox(disciplineName string, marks []string) (*scoring.BoxGame, error) {
+	discipline, err := scoring.LookupDiscipline(disciplineName)
+	if err != nil {
+		return nil, err
+	}
+	return scoring.BoxFromMarks(discipline, marks)
+}
+func (b Bowl) box() (*scoring.BoxGame, error) {
+	return newBox(b.Discipline, b.Marks)
+}
+func (a Archive) box() (*scoring.BoxGame, error) {
+	return newBox(a.Discipline, a.Marks)
+}
+func (b Bowl) tenPin() bool {
+	return b.Discipline == ""
+}
+func (b Bowl) over() bool {
+	if b.tenPin() {
+		return b.Times == 21
+	}
+	return b.Times == scoring.BoxSlots
+}
+
+// frameMarks returns the marks rolled in frame f.
+func (b Bowl) frameMarks(f int) []string {
+	marks, width := b.Pins[:], 2
+	if !b.tenPin() {
+		marks, width = b.Marks, 3
+	}
+	if width*f >= len(marks) {
+		return nil
+	}
+	end := width * (f + 1)
+	if f == scoring.NumFrames-1 || end > len(marks) {
+		end = len(marks)
+	}
+	var rolled []string
+	for _, mark := range marks[width*f : end] {
+		if mark != "yet" {
+			rolled = append(rolled, mark)
 		}
 	}
-	return m.Bowl.Scores
+	return rolled
 }
-func (m Model) maxScore() int {
-	if m.Bowl.Times < 19 {
-		if m.Bowl.Times%2 != 0 {
-			m.Bowl.Pins[m.Bowl.Times] = "/"
-			m.Bowl.Times++
+func (b Bowl) frame() int {
+	if b.tenPin() {
+		if b.Times > 18 {
+			return 9
 		}
-		for i := m.Bowl.Times; i <= 20; i++ {
-			if i > 17 {
-				m.Bowl.Pins[i] = "X"
-			} else if i%2 == 0 {
-				m.Bowl.Pins[i] = "X"
-			}
+		return b.Times / 2
+	}
+	if b.Times > 27 {
+		return 9
+	}
+	return b.Times / 3
+}
+
+// handicap is the handicap the game in progress is bowled with, earned by the
+// finished games of the same discipline, and whether there are enough of
+// them to establish an average.
+func (b Bowl) handicap() (int, bool) {
+	if b.Handicap == nil {
+		return 0, false
+	}
+	var totals []int
+	for _, a := range b.Archives {
+		if a.Discipline == b.Discipline {
+			totals = append(totals, a.Scores[10])
 		}
-	} else if m.Bowl.Times < 21 {
-		for i := m.Bowl.Times; i <= 20; i++ {
-			switch m.Bowl.Pins[i-1] {
-			case "X":
-				m.Bowl.Pins[i] = "X"
-			case "/":
-				m.Bowl.Pins[i] = "X"
-			default:
-				m.Bowl.Pins[i] = "/"
-			}
+	}
+	return b.Handicap.For(totals)
+}
+func (b Bowl) scratch() int {
+	for i := len(b.Scores) - 1; i > 0; i-- {
+		if b.Scores[i] != -1 {
+			return b.Scores[i]
 		}
 	}
-	m.Bowl.Times = 21
-	ms := m.score()
-	return ms[10]
-}
-func (m Model) addScore(str string) Bowl {
-	if m.Bowl.Times > 17 {
-		times := m.Bowl.Times
-		switch m.Bowl.Times {
-		case 18:
-			m.Bowl.Pins, m.Bowl.Times = m.strike(str)
-			if m.Bowl.Pins[times] == "X" {
-				m.Bowl.Times--
-			}
-		case 19:
-			if m.Bowl.Pins[times-1] == "X" {
-				m.Bowl.Pins, m.Bowl.Times = m.strike(str)
-				if m.Bowl.Pins[times] == "X" {
-					m.Bowl.Times--
-				}
-			} else {
-				m.Bowl.Pins, m.Bowl.Times = m.spare(str)
-				if m.Bowl.Pins[times] != "/" {
-					m.Bowl.Times = 21
-				}
-			}
-		case 20:
-			if m.Bowl.Pins[times-1] == "X" || m.Bowl.Pins[times-1] == "/" {
-				m.Bowl.Pins, m.Bowl.Times = m.strike(str)
-			} else {
-				m.Bowl.Pins, m.Bowl.Times = m.spare(str)
-			}
-			if m.Bowl.Pins[times] != "yet" {
-				m.Bowl.Times = 21
-			}
+	return 0
+}
+func perfect(disciplineName string) int {
+	if discipline, err := scoring.LookupDiscipline(disciplineName); err == nil {
+		return discipline.Max()
+	}
+	return 300
+}
+func (m Model) addBoxScore(str string) Bowl {
+	game, err := m.Bowl.box()
+	if err != nil {
+		m.logger.Error(err.Error())
+		return m.Bowl
+	}
+	if err := boxRollInput(game, str); err != nil {
+		return m.Bowl
+	}
+	before := m.Bowl
+	m.Bowl = m.applyBox(game)
+	m.publish("roll", before)
+	m.noteGame("roll", before)
+	return m.Bowl
+}
+func (m Model) applyBox(game *scoring.BoxGame) Bowl {
+	m.Bowl.Discipline = game.Discipline().Name
+	m.Bowl.Marks = game.Marks()
+	m.Bowl.Scores = game.Scores()
+	m.Bowl.MaxScore = game.MaxPossible()
+	m.Bowl.Times = game.Times()
+	return m.Bowl
+}
+func (m Model) undoBoxScore() Bowl {
+	game, err := m.Bowl.box()
+	if err != nil {
+		m.logger.Error(err.Error())
+		return m.Bowl
+	}
+	if err := game.Undo(); err != nil {
+		m.logger.Warn("Nothing to undo.")
+		return m.Bowl
+	}
+	m.logger.Info("Undo the last roll.")
+	before := m.Bowl
+	m.Bowl = m.applyBox(game)
+	m.publish("undo", before)
+	m.noteGame("undo", before)
+	return m.Bowl
+}
+func (m Model) nextDiscipline() Bowl {
+	if m.Bowl.Times != 0 {
+		m.logger.Warn("The discipline can only be changed before the first roll.")
+		return m.Bowl
+	}
+	names := []string{""}
+	for _, discipline := range scoring.Disciplines() {
+		names = append(names, discipline.Name)
+	}
+	next := ""
+	for i, name := range names {
+		if name == m.Bowl.Discipline {
+			next = names[(i+1)%len(names)]
 		}
-	} else if m.Bowl.Times%2 == 0 {
-		m.Bowl.Pins, m.Bowl.Times = m.strike(str)
+	}
+	before := m.Bowl
+	m.Bowl.Discipline = next
+	m.Bowl.Pins = initPins()
+	m.Bowl.Marks = nil
+	m.Bowl.Leaves = nil
+	m.Bowl.Scores = initScores()
+	m.Bowl.MaxScore = perfect(next)
+	if next == "" {
+		m.logger.Info("Play ten-pin.")
 	} else {
-		m.Bowl.Pins, m.Bowl.Times = m.spare(str)
+		m.Bowl.Marks = initMarks()
+		m.logger.Info(fmt.Sprintf("Play %s.", next))
+	}
+	m.noteGame("discipline", before)
+	return m.Bowl
+}
+func (m Model) undoScore() Bowl {
+	if !m.Bowl.tenPin() {
+		return m.undoBoxScore()
+	}
+	game, err := m.Bowl.game()
+	if err != nil {
+		m.logger.Error(err.Error())
+		return m.Bowl
+	}
+	if err := game.Undo(); err != nil {
+		m.logger.Warn("Nothing to undo.")
+		return m.Bowl
+	}
+	m.logger.Info("Undo the last roll.")
+	before := m.Bowl
+	m.Bowl = m.applyGame(game)
+	m.publish("undo", before)
+	m.noteGame("undo", before)
+	return m.Bowl
+}
+func (m Model) moveFrame(d int) (int, Bowl) {
+	if !m.Bowl.tenPin() {
+		m.logger.Warn("Frames can only be re-entered in ten-pin.")
+		return -1, record(scoring.New())
+	}
+	game, err := m.Bowl.game()
+	if err != nil {
+		m.logger.Error(err.Error())
+		return -1, record(scoring.New())
+	}
+	last := len(game.Frames()) - 1
+	frame := m.editFrame
+	if frame < 0 {
+		frame = last + 1
+	}
+	frame += d
+	if frame < 0 {
+		frame = 0
+	}
+	if frame > last {
+		return -1, record(scoring.New())
+	}
+	return frame, record(game.Rewind(frame))
+}
+func (m Model) editScore(roll func(*scoring.Game) error) (Bowl, Bowl, int) {
+	game, err := m.Bowl.game()
+	if err != nil {
+		m.logger.Error(err.Error())
+		return m.Bowl, record(scoring.New()), -1
+	}
+	edit, err := m.edit.game()
+	if err != nil {
+		m.logger.Error(err.Error())
+		return m.Bowl, record(scoring.New()), -1
+	}
+	if err := roll(edit); err != nil {
+		m.logger.Warn("Invalid value. Type again.")
+		return m.Bowl, m.edit, m.editFrame
+	}
+	frame := edit.Frames()[m.editFrame]
+	original := game.Frames()[m.editFrame]
+	if !frame.Complete && (original.Complete || len(frame.Balls) < len(original.Balls)) {
+		return m.Bowl, record(edit), m.editFrame
+	}
+	if err := game.ReplaceFrame(m.editFrame, frame.Balls); err != nil {
+		m.logger.Warn(fmt.Sprintf("Failed to re-enter frame %d.", m.editFrame+1))
+		return m.Bowl, record(game.Rewind(m.editFrame)), m.editFrame
+	}
+	m.logger.Info(fmt.Sprintf("Re-enter frame %d.", m.editFrame+1))
+	before := m.Bowl
+	m.Bowl = m.applyGame(game)
+	m.publish("edit", before)
+	m.noteEdit(before)
+	return m.Bowl, record(scoring.New()), -1
+}
+func (m Model) undoEdit() Bowl {
+	edit, err := m.edit.game()
+	if err != nil || len(edit.Frames()) <= m.editFrame {
+		return m.edit
+	}
+	edit.Undo()
+	return record(edit)
+}
+func (m Model) nextRules() Bowl {
+	if !m.Bowl.tenPin() {
+		m.logger.Warn("Rules only apply to ten-pin.")
+		return m.Bowl
+	}
+	if m.Bowl.Times != 0 {
+		m.logger.Warn("Rules can only be changed before the first roll.")
+		return m.Bowl
+	}
+	before := m.Bowl
+	current, err := scoring.LookupRules(m.Bowl.Rules)
+	if err != nil {
+		current = scoring.Traditional
+	}
+	ruleSets := scoring.RuleSets()
+	for i, rules := range ruleSets {
+		if rules == current {
+			m.Bowl.Rules = ruleSets[(i+1)%len(ruleSets)].Name()
+		}
+	}
+	m.logger.Info(fmt.Sprintf("Play with %s rules.", m.Bowl.Rules))
+	if game, err := m.Bowl.game(); err == nil {
+		m.Bowl = m.applyGame(game)
+	}
+	m.noteGame("rules", before)
+	return m.Bowl
+}
+func (m Model) nextVariant() Bowl {
+	if !m.Bowl.tenPin() {
+		m.logger.Warn("Variants only apply to ten-pin.")
+		return m.Bowl
+	}
+	if m.Bowl.Times != 0 {
+		m.logger.Warn("The variant can only be changed before the first roll.")
+		return m.Bowl
+	}
+	before := m.Bowl
+	current, err := scoring.LookupVariant(m.Bowl.Variant)
+	if err != nil {
+		current = scoring.Standard
 	}
-	m.Bowl.Scores = m.score()
-	m.Bowl.MaxScore = m.maxScore()
+	variants := scoring.Variants()
+	for i, variant := range variants {
+		if variant.Name == current.Name {
+			m.Bowl.Variant = variants[(i+1)%len(variants)].Name
+		}
+	}
+	m.logger.Info(fmt.Sprintf("Play the %s variant.", m.Bowl.Variant))
+	if game, err := m.Bowl.game(); err == nil {
+		m.Bowl = m.applyGame(game)
+	}
+	m.noteGame("variant", before)
 	return m.Bowl
 }
+func (m Model) standingDeck() scoring.Deck {
+	game, err := m.Bowl.game()
+	if m.editFrame >= 0 {
+		game, err = m.edit.game()
+	}
+	if err != nil {
+		return scoring.FullDeck
+	}
+	if deck, known := game.Deck(); known {
+		return deck
+	}
+	return scoring.FullDeck
+}
+func (m Model) scorePlaceholder() string {
+	switch {
+	case m.editFrame >= 0:
+		return fmt.Sprintf("Re-enter frame %d.", m.editFrame+1)
+	case m.Bowl.over():
+		return "Let's go to the next game!"
+	case m.Bowl.Discipline == scoring.FivePin.Name:
+		return "How many points were knocked down?"
+	}
+	return "How many pins were knocked down?"
+}
 func (m Model) nextGame() (Bowl, paginator.Model) {
+	before := m.Bowl
 	a := Archive{
-		Time:   time.Now().Format("2006/01/02 15:04:05 -0700 MST"),
-		Pins:   m.Bowl.Pins,
-		Scores: m.Bowl.Scores,
+		Time:       time.Now().Format("2006/01/02 15:04:05 -0700 MST"),
+		Discipline: m.Bowl.Discipline,
+		Pins:       m.Bowl.Pins,
+		Marks:      m.Bowl.Marks,
+		Leaves:     m.Bowl.Leaves,
+		Rules:      m.Bowl.Rules,
+		Variant:    m.Bowl.Variant,
+		Scores:     m.Bowl.Scores,
+	}
+	if game, err := m.Bowl.game(); err == nil && m.Bowl.tenPin() {
+		a.Splits, a.SplitConversions = game.SplitStats()
 	}
+	a.Handicap, _ = m.Bowl.handicap()
 	m.Bowl.Archives = append(m.Bowl.Archives, a)
 
 	m.Bowl.Pins = initPins()
+	m.Bowl.Marks = nil
+	if !m.Bowl.tenPin() {
+		m.Bowl.Marks = initMarks()
+	}
+	m.Bowl.Leaves = nil
 	m.Bowl.Scores = initScores()
-	m.Bowl.MaxScore = 300
+	m.Bowl.MaxScore = perfect(m.Bowl.Discipline)
 	m.Bowl.Times = 0
+	m.publish("next", before)
+	m.noteNext(before)
 
 	m.scoreSel.SetTotalPages(len(m.Bowl.Archives))
 	n := m.scoreSel.TotalPages - m.scoreSel.Page
@@ -447,17 +822,155 @@ func (m Model) nextGame() (Bowl, paginator.Model) {
 	return m.Bowl, m.scoreSel
 }
 
+func (m Model) rotate() (Bowl, int) {
+	m.lane[m.turn] = m.Bowl
+	for i := 1; i <= len(m.lane); i++ {
+		next := (m.turn + i) % len(m.lane)
+		if !m.lane[next].over() {
+			m.logger.Info(fmt.Sprintf("It's %s's turn.", m.lane[next].Name))
+			return m.lane[next], next
+		}
+	}
+	return m.Bowl, m.turn
+}
+
+// passedOn reports whether the last roll in the lane passed the turn on to
+// another bowler, so that undo and frame re-entry belong to the one before.
+func (m Model) passedOn() bool {
+	return len(m.lane) > 0 && m.lastTurn >= 0 && m.lastTurn != m.turn
+}
+
+// takeBack gives the lane back to the bowler who rolled last.
+func (m Model) takeBack() (Bowl, int) {
+	m.lane[m.turn] = m.Bowl
+	m.logger.Info(fmt.Sprintf("Back to %s.", m.lane[m.lastTurn].Name))
+	return m.lane[m.lastTurn], m.lastTurn
+}
+
+// handBack returns the turn taken back for re-entering a frame once the
+// frame is done with.
+func (m Model) handBack() (Bowl, int, int) {
+	if m.editFrame >= 0 || m.handback < 0 {
+		return m.Bowl, m.turn, m.handback
+	}
+	m.lane[m.turn] = m.Bowl
+	m.logger.Info(fmt.Sprintf("It's %s's turn.", m.lane[m.handback].Name))
+	return m.lane[m.handback], m.handback, -1
+}
+func (m Model) nextLaneGame() (Bowl, []Bowl) {
+	m.lane[m.turn] = m.Bowl
+	for i := range m.lane {
+		m.Bowl = m.lane[i]
+		m.lane[i], m.scoreSel = m.nextGame()
+	}
+	return m.lane[0], m.lane
+}
+
+// loadLane loads the bowlers of a lane as saved, with the problems found in
+// each one's data. A bowler saved without a name takes the one the lane was
+// entered with.
+func (m Model) loadLane(names string) ([]Bowl, [][]finding, error) {
+	var lane []Bowl
+	var issues [][]finding
+	for _, name := range strings.Split(names, ",") {
+		name = cleanName(name)
+		if name == "" {
+			continue
+		}
+		_, err := m.store.Load(name)
+		if errors.Is(err, errNoPlayer) {
+			bowl := initBowl()
+			bowl.Name = name
+			lane = append(lane, bowl)
+			issues = append(issues, nil)
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("load data of \"%s\": %w", name, err)
+		}
+		m.data = name
+		bowl, findings, err := m.inspect()
+		if err != nil {
+			return nil, nil, err
+		}
+		if bowl.Name == "" {
+			bowl.Name = name
+		}
+		lane = append(lane, bowl)
+		issues = append(issues, findings)
+	}
+	return lane, issues, nil
+}
+
+// laneRepair picks the first bowler on the lane whose problems are still to
+// be dealt with, or the first bowler when there are none left.
+func (m Model) laneRepair() (Bowl, int, []finding) {
+	for i, findings := range m.laneIssues {
+		if len(findings) > 0 {
+			return m.lane[i], i, findings
+		}
+	}
+	return m.lane[0], 0, nil
+}
+func (m Model) laneCmd(names string) tea.Cmd {
+	return func() tea.Msg {
+		lane, findings, err := m.loadLane(names)
+		if err != nil {
+			m.logger.Error(err.Error())
+			return errorMsg{err: err, retry: m.laneCmd(names)}
+		}
+		return laneMsg{lane: lane, findings: findings}
+	}
+}
+
+// writeLane saves every bowler on the lane but those whose problems are still
+// waiting to be dealt with, who are left as saved.
+func (m Model) writeLane() error {
+	for i, bowl := range m.lane {
+		if i != m.turn && i < len(m.laneIssues) && len(m.laneIssues[i]) > 0 {
+			continue
+		}
+		m.Bowl = bowl
+		if err := m.write(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+func (m Model) save() error {
+	if len(m.lane) > 0 {
+		m.lane[m.turn] = m.Bowl
+		return m.writeLane()
+	}
+	return m.write()
+}
+
+// saveCmd saves right away, so that saves land in the order they were made,
+// and turns a failure into an errorMsg whose retry saves again.
+func (m Model) saveCmd(quit bool) tea.Cmd {
+	err := m.save()
+	if err == nil {
+		return nil
+	}
+	m.logger.Error(err.Error())
+	retry := func() tea.Msg {
+		if cmd := m.saveCmd(quit); cmd != nil {
+			return cmd()
+		}
+		return savedMsg{quit: quit}
+	}
+	return func() tea.Msg { return errorMsg{err: err, retry: retry} }
+}
+
 func (m Model) nameCheck() string {
-	if m.nameInput.Value() == "" {
+	name := cleanName(m.nameInput.Value())
+	if name == "" {
 		return m.Bowl.Name
-	} else {
-		re := regexp.MustCompile(`[\\/:*?"<>|]`)
-		if re.MatchString(m.nameInput.Value()) {
-			m.logger.Error("Found an invalid value. Initialize to appropriate values.")
-			return re.ReplaceAllString(m.nameInput.Value(), "-")
-		}
-		return m.nameInput.Value()
 	}
+	if name != m.nameInput.Value() {
+		m.logger.Error("Found an invalid value. Initialize to appropriate values.")
+	}
+	return name
 }
 func pinsCheck(pins [21]string) ([21]string, bool) {
 	for i, pin := range pins {
@@ -469,10 +982,12 @@ func pinsCheck(pins [21]string) ([21]string, bool) {
 			if i > 18 {
 				switch i {
 				case 19:
-					if pins[i-1] == "X" {
+					if pins[i-1] == "X" || pins[i-1] == "N" {
 						switch pin {
 						case "X":
 							continue
+						case "N":
+							continue
 						case "G":
 							continue
 						case "F":
@@ -497,10 +1012,12 @@ func pinsCheck(pins [21]string) ([21]string, bool) {
 						}
 					}
 				case 20:
-					if pins[i-1] == "X" || pins[i-1] == "/" {
+					if pins[i-1] == "X" || pins[i-1] == "N" || pins[i-1] == "/" {
 						switch pin {
 						case "X":
 							continue
+						case "N":
+							continue
 						case "G":
 							continue
 						case "F":
@@ -529,6 +1046,8 @@ func pinsCheck(pins [21]string) ([21]string, bool) {
 				switch pin {
 				case "X":
 					continue
+				case "N":
+					continue
 				case "G":
 					continue
 				case "F":
@@ -556,91 +1075,62 @@ func pinsCheck(pins [21]string) ([21]string, bool) {
 	}
 	return pins, true
 }
-func scoresCheck(scores [11]int) ([11]int, bool) {
+func scoresCheck(scores [11]int, frameMax int) ([11]int, bool) {
 	lim := 0
 	for _, score := range scores {
 		if score > lim {
 			return initScores(), false
 		}
-		lim += 30
+		lim += frameMax
 	}
 	return scores, true
 }
-func (m Model) dataCheck() Bowl {
-	msg := "Found an invalid value. Initialize to appropriate values."
-	if m.Bowl.Name == "" {
-		m.logger.Error(msg)
-		return initBowl()
+func (m Model) write() error {
+	if err := m.store.Save(m.Bowl); err != nil {
+		return fmt.Errorf("save data of \"%s\": %w", m.Bowl.Name, err)
 	}
-	if m.Bowl.MaxScore > 300 {
-		m.logger.Error(msg)
-		return initBowl()
+	m.logger.Info(fmt.Sprintf("Save data of \"%s\".", m.Bowl.Name))
+	return nil
+}
+func (m Model) inspect() (Bowl, []finding, error) {
+	name := strings.TrimSuffix(filepath.Base(m.data), ".json")
+	bowl, err := m.store.Load(name)
+	if err != nil {
+		return bowl, nil, fmt.Errorf("load data of \"%s\": %w", name, err)
 	}
-	if m.Bowl.Times > 21 {
-		m.logger.Error(msg)
-		return initBowl()
+	m.logger.Info(fmt.Sprintf("Load data of \"%s\".", name))
+	findings := validate(bowl)
+	for _, f := range findings {
+		m.logger.Warn(fmt.Sprintf("Found a problem in \"%s\": %s.", name, f))
 	}
-
-	flg := true
-	m.Bowl.Pins, flg = pinsCheck(m.Bowl.Pins)
-	if !flg {
-		m.Bowl.Scores = initScores()
-		m.logger.Error(msg)
-	}
-	m.Bowl.Scores, flg = scoresCheck(m.Bowl.Scores)
-	if !flg {
-		m.Bowl.Pins = initPins()
-		m.logger.Error(msg)
-	}
-	for i := range m.Bowl.Archives {
-		m.Bowl.Archives[i].Pins, flg = pinsCheck(m.Bowl.Archives[i].Pins)
-		if !flg {
-			m.Bowl.Archives[i].Scores = initScores()
-			m.logger.Error(msg)
-		}
-		m.Bowl.Archives[i].Scores, flg = scoresCheck(m.Bowl.Archives[i].Scores)
-		if !flg {
-			m.Bowl.Archives[i].Pins = initPins()
-			m.logger.Error(msg)
-		}
-	}
-	return m.Bowl
+	return bowl, findings, nil
 }
-func (m Model) write() {
-	if _, err := os.Stat("data"); err != nil {
-		if e := os.Mkdir("data", 0777); e == nil {
-			m.logger.Info("Create a directory named \"data\".")
-		} else {
-			m.logger.Fatal("Failed to create a directory named \"data\".")
-		}
-	}
-	if file, err := os.Create(filepath.Join("data", fmt.Sprintf("%s.json", m.Bowl.Name))); err == nil {
-		m.logger.Info(fmt.Sprintf("Create a JSON file named \"%s.json\".", m.Bowl.Name))
-		encoder := json.NewEncoder(file)
-		encoder.SetIndent("", "  ")
-		if e := encoder.Encode(m.Bowl); e == nil {
-			m.logger.Info("Encode data.")
-		} else {
-			m.logger.Fatal("Failed to encode data.")
+func (m Model) loadCmd() tea.Cmd {
+	return func() tea.Msg {
+		bowl, findings, err := m.inspect()
+		if err != nil {
+			m.logger.Error(err.Error())
+			return errorMsg{err: err, retry: m.loadCmd()}
 		}
-	} else {
-		m.logger.Fatal(fmt.Sprintf("Failed to create a JSON file named \"%s.json\".", m.Bowl.Name))
+		return loadMsg{bowl: bowl, findings: findings}
 	}
 }
-func (m Model) read() Bowl {
-	fileName := filepath.Base(m.data)
-	if file, err := os.Open(m.data); err == nil {
-		m.logger.Info(fmt.Sprintf("Reading a JSON file named \"%s\".", fileName))
-		if e := json.NewDecoder(file).Decode(&m.Bowl); e == nil {
-			m.logger.Info("Decode data.")
-		} else {
-			m.logger.Fatal("Failed to decode data.")
-		}
-	} else {
-		m.logger.Fatal(fmt.Sprintf("Failed to reading a JSON file named \"%s\".", fileName))
+
+// read loads the player of m.data with every game scored from its rolls
+// rather than from the saved scores, repairing in memory whatever else the
+// saved data gets wrong. It is only for showing a player: one that is about
+// to be changed and saved is loaded with loadClean.
+func (m Model) read() (Bowl, error) {
+	bowl, findings, err := m.inspect()
+	if err != nil || len(findings) == 0 {
+		return bowl, err
 	}
-	m.Bowl = m.dataCheck()
-	return m.Bowl
+	m.Bowl = bowl
+	return m.repair(findings), nil
+}
+func (m Model) picksFiles() bool {
+	_, ok := m.store.(jsonStore)
+	return ok
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -653,11 +1143,73 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if didSelect, path := m.dataSel.DidSelectFile(msg); didSelect {
 			m.data = path
 		}
+	case "laneMode":
+		m.laneInput, cmd = m.laneInput.Update(msg)
 	case "mgmtScore":
-		m.scoreInput, cmd = m.scoreInput.Update(msg)
+		if !m.deckMode {
+			m.scoreInput, cmd = m.scoreInput.Update(msg)
+		}
 	}
 
 	switch msg := msg.(type) {
+	case signalMsg:
+		m.logger.Info(fmt.Sprintf("Received %s.", msg.sig))
+		if m.scene == "mgmtScore" || m.scene == "statsScene" || m.scene == "errorScene" && m.resume == "mgmtScore" {
+			if err := m.save(); err != nil {
+				m.logger.Error(err.Error())
+			}
+		}
+		m.logger.Info("Close the app.")
+		return m, tea.Quit
+	case errorMsg:
+		if m.scene != "errorScene" {
+			m.resume = m.scene
+		}
+		m.failure = msg
+		m.errorKeys.retry.SetEnabled(msg.retry != nil)
+		m.logger.Info("\"Error\" scene is selected.")
+		m.scene = "errorScene"
+	case loadMsg:
+		m.Bowl, m.findings = msg.bowl, msg.findings
+		if len(m.findings) > 0 {
+			m.logger.Info("\"Repair\" scene is selected.")
+			m.repairKeys.keep.SetEnabled(!anyFatal(m.findings))
+			m.scene = "repairScene"
+			break
+		}
+		m.scoreSel.SetTotalPages(len(m.Bowl.Archives))
+		m.scene = "mgmtScore"
+	case laneMsg:
+		m.lane, m.laneIssues = msg.lane, msg.findings
+		if len(m.lane) == 0 {
+			m.scene = "laneMode"
+			break
+		}
+		m.lastTurn, m.handback = -1, -1
+		m.Bowl, m.turn, m.findings = m.laneRepair()
+		if len(m.findings) > 0 {
+			m.logger.Info("\"Repair\" scene is selected.")
+			m.repairKeys.keep.SetEnabled(!anyFatal(m.findings))
+			m.scene = "repairScene"
+			break
+		}
+		m.scene = "mgmtScore"
+	case leagueMsg:
+		m.league = msg.league
+		m.week = m.league.currentWeek()
+		m.logger.Info("\"League\" scene is selected.")
+		m.scene = "leagueScene"
+	case exportedMsg:
+		m.notice = fmt.Sprintf("Exported %s.", strings.Join(msg.paths, " and "))
+		if m.scene == "errorScene" {
+			m.scene = m.resume
+		}
+	case savedMsg:
+		if msg.quit {
+			m.logger.Info("Close the app.")
+			return m, tea.Quit
+		}
+		m.scene = m.resume
 	case tea.KeyMsg:
 		switch m.scene {
 		case "modeSelect":
@@ -671,7 +1223,17 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.scene = "dataGenMode"
 				case 1:
 					m.logger.Info("\"Data Selection\" mode is selected.")
+					if !m.picksFiles() {
+						m.playerSel = m.initPlayerSel()
+					}
 					m.scene = "dataSelMode"
+				case 2:
+					m.logger.Info("\"Lane\" mode is selected.")
+					m.scene = "laneMode"
+				case 3:
+					m.logger.Info("\"League Selection\" mode is selected.")
+					m.leagueSel = m.initLeagueSel()
+					m.scene = "leagueSelMode"
 				}
 			case key.Matches(msg, m.selectKeys.next):
 				m.modeSel.CursorUp()
@@ -696,14 +1258,36 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, tea.Quit
 			}
 
+		case "laneMode":
+			switch {
+			case key.Matches(msg, m.inputKeys.enter):
+				m.logger.Info("Current mode is \"Lane\".")
+				m.logger.Info(fmt.Sprintf("\"%s\" is typed.", m.laneInput.Value()))
+				names := m.laneInput.Value()
+				m.laneInput.Reset()
+				return m, m.laneCmd(names)
+			case key.Matches(msg, m.inputKeys.quit):
+				m.logger.Info("Close the app.")
+				return m, tea.Quit
+			}
+
 		case "dataSelMode":
 			m.selectKeys = upDownKeys
 			switch {
 			case key.Matches(msg, m.selectKeys.enter):
 				m.logger.Info("Current mode is \"Data Selection\".")
-				m.Bowl = m.read()
-				m.scoreSel.SetTotalPages(len(m.Bowl.Archives))
-				m.scene = "mgmtScore"
+				if !m.picksFiles() {
+					item, ok := m.playerSel.SelectedItem().(dish)
+					if !ok {
+						break
+					}
+					m.data = item.state
+				}
+				return m, m.loadCmd()
+			case !m.picksFiles() && key.Matches(msg, m.selectKeys.next):
+				m.playerSel.CursorUp()
+			case !m.picksFiles() && key.Matches(msg, m.selectKeys.prev):
+				m.playerSel.CursorDown()
 			case key.Matches(msg, m.selectKeys.quit):
 				m.logger.Info("Close the app.")
 				return m, tea.Quit
@@ -711,34 +1295,212 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case "mgmtScore":
 			m.selectKeys = rightLeftKeys
-			if m.Bowl.Times == 21 {
+			m.notice = ""
+			if m.Bowl.over() && m.editFrame < 0 &&
+				!key.Matches(msg, m.editKeys.undo, m.editKeys.up, m.editKeys.down, m.editKeys.deck, m.gameKeys.stats, m.gameKeys.export) {
 				m.logger.Info("Game start.")
-				m.Bowl, m.scoreSel = m.nextGame()
+				if len(m.lane) > 0 {
+					m.Bowl, m.lane = m.nextLaneGame()
+					m.turn, m.lastTurn = 0, -1
+				} else {
+					m.Bowl, m.scoreSel = m.nextGame()
+				}
+				cmd = tea.Batch(cmd, m.saveCmd(false))
 			}
 			switch {
 			case key.Matches(msg, m.selectKeys.enter):
 				m.logger.Info("Current mode is \"Management Score\".")
-				m.logger.Info(fmt.Sprintf("\"%s\" is typed.", m.scoreInput.Value()))
-				times := m.Bowl.Times
-				m.Bowl = m.addScore(m.scoreInput.Value())
-				if times != m.Bowl.Times {
-					m.logger.Info("Update Score.")
+				if m.deckMode {
+					m.logger.Info(fmt.Sprintf("Pins %v are left.", m.deck.Pins()))
 				} else {
-					m.logger.Warn("Invalid value. Type again.")
+					m.logger.Info(fmt.Sprintf("\"%s\" is typed.", m.scoreInput.Value()))
+				}
+				if m.editFrame >= 0 {
+					m.Bowl, m.edit, m.editFrame = m.editScore(m.input())
+					cmd = tea.Batch(cmd, m.saveCmd(false))
+					m.Bowl, m.turn, m.handback = m.handBack()
+				} else {
+					times, frame := m.Bowl.Times, m.Bowl.frame()
+					if m.Bowl.tenPin() {
+						m.Bowl = m.addScore(m.input())
+					} else {
+						m.Bowl = m.addBoxScore(m.scoreInput.Value())
+					}
+					if times != m.Bowl.Times {
+						m.logger.Info("Update Score.")
+						m.lastTurn = m.turn
+						if len(m.lane) > 0 && (m.Bowl.over() || frame != m.Bowl.frame()) {
+							m.Bowl, m.turn = m.rotate()
+						}
+						cmd = tea.Batch(cmd, m.saveCmd(false))
+					} else {
+						m.logger.Warn("Invalid value. Type again.")
+					}
 				}
 				m.scoreInput.Reset()
-				if m.Bowl.Times == 21 {
+				if m.Bowl.over() && m.editFrame < 0 {
 					m.logger.Info("Game over.")
-					m.scoreInput.Placeholder = "Let's go to the next game!"
+				}
+			case key.Matches(msg, m.editKeys.undo):
+				if m.editFrame >= 0 {
+					m.edit = m.undoEdit()
 				} else {
-					m.scoreInput.Placeholder = "How many pins were knocked down?"
+					if m.passedOn() {
+						m.Bowl, m.turn = m.takeBack()
+					}
+					m.Bowl = m.undoScore()
+					cmd = tea.Batch(cmd, m.saveCmd(false))
+				}
+			case key.Matches(msg, m.editKeys.up):
+				if m.editFrame < 0 && m.passedOn() {
+					m.handback = m.turn
+					m.Bowl, m.turn = m.takeBack()
+				}
+				m.editFrame, m.edit = m.moveFrame(-1)
+				m.Bowl, m.turn, m.handback = m.handBack()
+			case key.Matches(msg, m.editKeys.down):
+				m.editFrame, m.edit = m.moveFrame(1)
+				m.Bowl, m.turn, m.handback = m.handBack()
+			case key.Matches(msg, m.editKeys.deck):
+				if m.Bowl.tenPin() {
+					m.deckMode = !m.deckMode
+				} else {
+					m.logger.Warn("The pin deck is only available in ten-pin.")
 				}
+			case key.Matches(msg, m.gameKeys.rules):
+				m.Bowl = m.nextRules()
+			case key.Matches(msg, m.gameKeys.variant):
+				m.Bowl = m.nextVariant()
+			case key.Matches(msg, m.gameKeys.discipline):
+				m.Bowl = m.nextDiscipline()
+			case key.Matches(msg, m.gameKeys.export):
+				cmd = tea.Batch(cmd, m.exportCmd())
+			case key.Matches(msg, m.gameKeys.stats):
+				m.logger.Info("\"Statistics\" scene is selected.")
+				m.scene = "statsScene"
+			case m.deckMode && regexp.MustCompile(`^[0-9]$`).MatchString(msg.String()):
+				pin, _ := strconv.Atoi(msg.String())
+				if pin == 0 {
+					pin = 10
+				}
+				m.deck = m.deck.Toggle(pin)
+				return m, cmd
 			case key.Matches(msg, m.selectKeys.next):
 				m.scoreSel.PrevPage()
 			case key.Matches(msg, m.selectKeys.prev):
 				m.scoreSel.NextPage()
 			case key.Matches(msg, m.selectKeys.quit):
-				m.write()
+				if save := m.saveCmd(true); save != nil {
+					return m, save
+				}
+				m.logger.Info("Close the app.")
+				return m, tea.Quit
+			}
+			m.scoreInput.Placeholder = m.scorePlaceholder()
+			m.deck = m.standingDeck()
+
+		case "repairScene":
+			switch {
+			case key.Matches(msg, m.repairKeys.keep):
+				m.logger.Info("Keep the data as it is.")
+			case key.Matches(msg, m.repairKeys.repair):
+				m.logger.Info("Repair the data.")
+				m.Bowl = m.repair(m.findings)
+				m.noteSnapshot("repair")
+				cmd = tea.Batch(cmd, m.saveCmd(false))
+			case key.Matches(msg, m.repairKeys.discard):
+				m.logger.Info("Discard the games with problems.")
+				m.Bowl = m.discard(m.findings)
+				m.noteSnapshot("discard")
+				cmd = tea.Batch(cmd, m.saveCmd(false))
+			case key.Matches(msg, m.repairKeys.quit):
+				m.logger.Info("Close the app.")
+				return m, tea.Quit
+			default:
+				return m, cmd
+			}
+			m.findings = nil
+			if len(m.lane) > 0 {
+				m.lane[m.turn], m.laneIssues[m.turn] = m.Bowl, nil
+				if m.Bowl, m.turn, m.findings = m.laneRepair(); len(m.findings) > 0 {
+					m.repairKeys.keep.SetEnabled(!anyFatal(m.findings))
+					return m, cmd
+				}
+			}
+			m.scoreSel.SetTotalPages(len(m.Bowl.Archives))
+			m.scene = "mgmtScore"
+
+		case "errorScene":
+			switch {
+			case key.Matches(msg, m.errorKeys.retry):
+				m.logger.Info("Retry.")
+				return m, m.failure.retry
+			case key.Matches(msg, m.errorKeys.other) && m.resume == "leagueSelMode":
+				m.logger.Info("\"League Selection\" mode is selected.")
+				m.leagueSel = m.initLeagueSel()
+				m.scene = "leagueSelMode"
+			case key.Matches(msg, m.errorKeys.other):
+				m.logger.Info("\"Data Selection\" mode is selected.")
+				m.lane = nil
+				if !m.picksFiles() {
+					m.playerSel = m.initPlayerSel()
+				}
+				m.scene = "dataSelMode"
+			case key.Matches(msg, m.errorKeys.menu):
+				m.logger.Info("\"Mode Selection\" is selected.")
+				m.lane = nil
+				m.scene = "modeSelect"
+			case key.Matches(msg, m.errorKeys.quit):
+				m.logger.Info("Close the app.")
+				return m, tea.Quit
+			}
+
+		case "leagueSelMode":
+			m.selectKeys = upDownKeys
+			switch {
+			case key.Matches(msg, m.selectKeys.enter):
+				m.logger.Info("Current mode is \"League Selection\".")
+				item, ok := m.leagueSel.SelectedItem().(dish)
+				if !ok {
+					break
+				}
+				return m, m.leagueCmd(item.state)
+			case key.Matches(msg, m.selectKeys.next):
+				m.leagueSel.CursorUp()
+			case key.Matches(msg, m.selectKeys.prev):
+				m.leagueSel.CursorDown()
+			case key.Matches(msg, m.selectKeys.quit):
+				m.logger.Info("Close the app.")
+				return m, tea.Quit
+			}
+
+		case "leagueScene":
+			m.selectKeys = rightLeftKeys
+			switch {
+			case key.Matches(msg, m.selectKeys.enter):
+				m.logger.Info("\"Mode Selection\" is selected.")
+				m.scene = "modeSelect"
+			case key.Matches(msg, m.selectKeys.next):
+				if m.week > 0 {
+					m.week--
+				}
+			case key.Matches(msg, m.selectKeys.prev):
+				if m.week < len(m.league.Weeks)-1 {
+					m.week++
+				}
+			case key.Matches(msg, m.selectKeys.quit):
+				m.logger.Info("Close the app.")
+				return m, tea.Quit
+			}
+
+		case "statsScene":
+			switch {
+			case key.Matches(msg, m.inputKeys.enter):
+				m.scene = "mgmtScore"
+			case key.Matches(msg, m.inputKeys.quit):
+				if save := m.saveCmd(true); save != nil {
+					return m, save
+				}
 				m.logger.Info("Close the app.")
 				return m, tea.Quit
 			}
@@ -747,25 +1509,61 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+func markDrawing(pin string, split bool, free bool) string {
+	switch {
+	case pin == "yet":
+		return " "
+	case pin == "N":
+		return noTapStyle.Render("X")
+	case free:
+		return freeStyle.Render(pin)
+	case split:
+		return splitStyle.Render(pin)
+	}
+	return pin
+}
 func (m Model) scoreDrawing() string {
+	if !m.Bowl.tenPin() {
+		return m.boxScoreDrawing()
+	}
 	scoreDrawing := strings.Builder{}
 	scoreDrawing.WriteString("┏━━━┳━━━┳━━━┳━━━┳━━━┳━━━┳━━━┳━━━┳━━━┳━━━━━┓┏━━━━━┓\n")
-	if m.Bowl.Times != 21 {
-		scoreDrawing.WriteString("┃ 1 ┃ 2 ┃ 3 ┃ 4 ┃ 5 ┃ 6 ┃ 7 ┃ 8 ┃ 9 ┃ 10  ┃┃ MAX ┃\n")
+	framesLine := "┃"
+	for i := 1; i <= 10; i++ {
+		frameStr := fmt.Sprintf(" %d ", i)
+		if i == 10 {
+			frameStr = " 10  "
+		}
+		if i-1 == m.editFrame {
+			frameStr = lipgloss.NewStyle().Foreground(docColor).Render(frameStr)
+		}
+		framesLine = fmt.Sprintf("%s%s┃", framesLine, frameStr)
+	}
+	if !m.Bowl.over() {
+		scoreDrawing.WriteString(fmt.Sprintf("%s┃ MAX ┃\n", framesLine))
 	} else {
-		scoreDrawing.WriteString("┃ 1 ┃ 2 ┃ 3 ┃ 4 ┃ 5 ┃ 6 ┃ 7 ┃ 8 ┃ 9 ┃ 10  ┃┃ RES ┃\n")
+		scoreDrawing.WriteString(fmt.Sprintf("%s┃ RES ┃\n", framesLine))
 	}
 	scoreDrawing.WriteString("┗━━━┻━━━┻━━━┻━━━┻━━━┻━━━┻━━━┻━━━┻━━━┻━━━━━┛┗━━━━━┛\n")
 
 	scoreDrawing.WriteString("┏━┳━┳━┳━┳━┳━┳━┳━┳━┳━┳━┳━┳━┳━┳━┳━┳━┳━┳━┳━┳━┓┏━━━━━┓\n")
-	pinsLine := "┃"
-	for _, pin := range m.Bowl.Pins {
-		pinStr := ""
-		if pin == "yet" {
-			pinStr = " "
-		} else {
-			pinStr = pin
+	pins := m.Bowl.Pins
+	splits, free := slotsOf(m.Bowl.game())
+	if m.editFrame >= 0 {
+		editSplits, editFree := slotsOf(m.edit.game())
+		end := 2*m.editFrame + 2
+		if m.editFrame == 9 {
+			end = 21
 		}
+		for i := 2 * m.editFrame; i < end; i++ {
+			pins[i] = m.edit.Pins[i]
+			splits[i] = editSplits[i]
+			free[i] = editFree[i]
+		}
+	}
+	pinsLine := "┃"
+	for i, pin := range pins {
+		pinStr := markDrawing(pin, splits[i], free[i])
 		pinsLine = fmt.Sprintf("%s%s┃", pinsLine, pinStr)
 	}
 	scoreDrawing.WriteString(fmt.Sprintf("%s┃     ┃\n", pinsLine))
@@ -799,11 +1597,79 @@ func (m Model) scoreDrawing() string {
 	}
 	scoreDrawing.WriteString(fmt.Sprintf("%s┃     ┃\n", scoresLine))
 	scoreDrawing.WriteString("┗━━━┻━━━┻━━━┻━━━┻━━━┻━━━┻━━━┻━━━┻━━━┻━━━━━┛┗━━━━━┛\n")
-
+	scoreDrawing.WriteString(m.handicapDrawing())
+	scoreDrawing.WriteString(m.summaryDrawing())
+	return scoreDrawing.String()
+}
+func boxLines(marks []string, scores [11]int) []string {
+	lines := []string{"┏", "┃", "┣", "┃", "┃", "┗"}
+	for f := 0; f < 10; f++ {
+		top, middle, bottom := "┳", "╋", "┻"
+		if f == 9 {
+			top, middle, bottom = "┓", "┫", "┛"
+		}
+		lines[0] += "━━━━━━" + top
+		lines[1] += fmt.Sprintf("  %-2d  ┃", f+1)
+		lines[2] += "━━━━━━" + middle
+		marksStr := ""
+		for j := 0; j < 3; j++ {
+			mark := "yet"
+			if 3*f+j < len(marks) {
+				mark = marks[3*f+j]
+			}
+			markStr := markDrawing(mark, false, false)
+			marksStr += strings.Repeat(" ", 2-lipgloss.Width(markStr)) + markStr
+		}
+		lines[3] += marksStr + "┃"
+		scoreStr := ""
+		if scores[f+1] != -1 {
+			scoreStr = strconv.Itoa(scores[f+1])
+		}
+		lines[4] += fmt.Sprintf("%5s ┃", scoreStr)
+		lines[5] += "━━━━━━" + bottom
+	}
+	return lines
+}
+func (m Model) boxScoreDrawing() string {
+	boxScoreDrawing := strings.Builder{}
+	result := "MAX"
+	if m.Bowl.over() {
+		result = "RES"
+	}
+	side := []string{
+		"┏━━━━━┓",
+		fmt.Sprintf("┃ %s ┃", result),
+		"┣━━━━━┫",
+		fmt.Sprintf("┃ %3d ┃", m.Bowl.MaxScore),
+		"┃     ┃",
+		"┗━━━━━┛",
+	}
+	for i, line := range boxLines(m.Bowl.Marks, m.Bowl.Scores) {
+		boxScoreDrawing.WriteString(fmt.Sprintf("%s%s\n", line, side[i]))
+	}
+	boxScoreDrawing.WriteString(m.handicapDrawing())
+	boxScoreDrawing.WriteString(m.summaryDrawing())
+	return boxScoreDrawing.String()
+}
+func (m Model) handicapDrawing() string {
+	if m.Bowl.Handicap == nil {
+		return ""
+	}
+	scratch := m.Bowl.scratch()
+	handicap, ok := m.Bowl.handicap()
+	if !ok {
+		return fmt.Sprintf("    Scratch:%-03s  Hdcp:---  (after %d games)\n",
+			strconv.Itoa(scratch), m.Bowl.Handicap.Games)
+	}
+	return fmt.Sprintf("    Scratch:%-03s  Hdcp:%-03s  With hdcp:%-03s\n",
+		strconv.Itoa(scratch), strconv.Itoa(handicap), strconv.Itoa(scratch+handicap))
+}
+func (m Model) summaryDrawing() string {
+	summaryDrawing := strings.Builder{}
 	archivesLen := len(m.Bowl.Archives)
 	if archivesLen > 0 {
 		high := 0
-		low := 300
+		low := m.Bowl.Archives[0].Scores[10]
 		sum := 0
 
 		for _, archive := range m.Bowl.Archives {
@@ -817,7 +1683,7 @@ func (m Model) scoreDrawing() string {
 			sum += a
 		}
 		avg := sum / archivesLen
-		scoreDrawing.WriteString(fmt.Sprintf(
+		summaryDrawing.WriteString(fmt.Sprintf(
 			"    Game:%-02s  Total:%-04s  Avg:%-03s  H/G:%-03s  L/G:%-03s\n\n",
 			strconv.Itoa(archivesLen+1),
 			strconv.Itoa(sum),
@@ -826,31 +1692,37 @@ func (m Model) scoreDrawing() string {
 			strconv.Itoa(low),
 		))
 	} else {
-		scoreDrawing.WriteString("    Game:1   Total:----  Avg:---  H/G:---  L/G:---\n\n")
+		summaryDrawing.WriteString("    Game:1   Total:----  Avg:---  H/G:---  L/G:---\n\n")
 	}
-	return scoreDrawing.String()
+	return summaryDrawing.String()
 }
 func (m Model) archivesScoreDrawing() string {
 	archiveScoresDrawing := strings.Builder{}
 	start, end := m.scoreSel.GetSliceBounds(len(m.Bowl.Archives))
 	for i, arc := range m.Bowl.Archives[start:end] {
-		archiveScoresDrawing.WriteString(fmt.Sprintf(" Game %-06s[%s]\n", strconv.Itoa(start+i+1), arc.Time))
+		if arc.Handicap > 0 {
+			archiveScoresDrawing.WriteString(fmt.Sprintf(" Game %-06s[%s]  Hdcp:%d  With hdcp:%d\n", strconv.Itoa(start+i+1), arc.Time, arc.Handicap, arc.Scores[10]+arc.Handicap))
+		} else {
+			archiveScoresDrawing.WriteString(fmt.Sprintf(" Game %-06s[%s]\n", strconv.Itoa(start+i+1), arc.Time))
+		}
+		if arc.Discipline != "" {
+			for _, line := range boxLines(arc.Marks, arc.Scores) {
+				archiveScoresDrawing.WriteString(fmt.Sprintf("%s\n", line))
+			}
+			continue
+		}
 		archiveScoresDrawing.WriteString("┏━┳━┳━┳━┳━┳━┳━┳━┳━┳━┳━┳━┳━┳━┳━┳━┳━┳━┳━┳━┳━┓\n")
 		archivePinsLine := "┃"
-		for _, pin := range m.Bowl.Archives[i].Pins {
-			archivePinStr := ""
-			if pin == "yet" {
-				archivePinStr = " "
-			} else {
-				archivePinStr = pin
-			}
+		archiveSplits, archiveFree := slotsOf(arc.game())
+		for j, pin := range arc.Pins {
+			archivePinStr := markDrawing(pin, archiveSplits[j], archiveFree[j])
 			archivePinsLine = fmt.Sprintf("%s%s┃", archivePinsLine, archivePinStr)
 		}
 		archiveScoresDrawing.WriteString(fmt.Sprintf("%s\n", archivePinsLine))
 		archiveScoresDrawing.WriteString("┃ ┗━┫ ┗━┫ ┗━┫ ┗━┫ ┗━┫ ┗━┫ ┗━┫ ┗━┫ ┗━╋━┻━┻━┫\n")
 
 		archiveScoresLine := "┃"
-		for i, score := range m.Bowl.Archives[i].Scores {
+		for i, score := range arc.Scores {
 			if i == 0 {
 				continue
 			}
@@ -877,6 +1749,78 @@ func (m Model) archivesScoreDrawing() string {
 	return archiveScoresDrawing.String()
 }
 
+func (m Model) archiveStats() scoring.Stats {
+	var games []*scoring.Game
+	for _, arc := range m.Bowl.Archives {
+		if arc.Discipline != "" {
+			continue
+		}
+		if game, err := arc.game(); err == nil {
+			games = append(games, game)
+		}
+	}
+	return scoring.Summarize(games)
+}
+func (m Model) statsScene() string {
+	statsScene := strings.Builder{}
+	st := m.archiveStats()
+	if st.Games == 0 {
+		statsScene.WriteString(lipgloss.NewStyle().Foreground(docInactiveColor).Render("   No games have been archived yet."))
+		statsScene.WriteString("\n\n")
+		return statsScene.String()
+	}
+	statsScene.WriteString("┏━━━━━━━━━━━━━━━━┳━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━┓\n")
+	for _, row := range [][2]string{
+		{"Games", strconv.Itoa(st.Games)},
+		{"Strike", fmt.Sprintf("%5.1f%%  (%d/%d)", 100*st.StrikeRate(), st.Strikes, st.StrikeChances)},
+		{"Spare", fmt.Sprintf("%5.1f%%  (%d/%d)", 100*st.SpareRate(), st.Spares, st.SpareChances)},
+		{"Open frame", fmt.Sprintf("%5.1f%%  (%d/%d)", 100*st.OpenRate(), st.OpenFrames, st.Frames)},
+		{"First ball avg", fmt.Sprintf("%5.2f", st.FirstBallAvg())},
+		{"Doubles", strconv.Itoa(st.Doubles)},
+		{"Turkeys", strconv.Itoa(st.Turkeys)},
+		{"Clean games", strconv.Itoa(st.CleanGames)},
+		{"10th frame avg", fmt.Sprintf("%5.2f", st.TenthFrameAvg())},
+		{"10th strike", fmt.Sprintf("%5.1f%%  (%d/%d)", 100*st.TenthStrikeRate(), st.TenthFrameStrikes, st.TenthFrameChances)},
+	} {
+		statsScene.WriteString(fmt.Sprintf("┃ %-14s ┃ %-28s ┃\n", row[0], row[1]))
+	}
+	statsScene.WriteString("┗━━━━━━━━━━━━━━━━┻━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━┛\n\n")
+	return statsScene.String()
+}
+
+func (m Model) repairScene() string {
+	repairScene := strings.Builder{}
+	if len(m.lane) > 0 {
+		repairScene.WriteString(fmt.Sprintf("   Found %d problems in the saved data of %s.\n\n", len(m.findings), m.Bowl.Name))
+	} else {
+		repairScene.WriteString(fmt.Sprintf("   Found %d problems in the saved data.\n\n", len(m.findings)))
+	}
+	for i, f := range m.findings {
+		if i == 12 {
+			repairScene.WriteString(fmt.Sprintf("   ... and %d more\n", len(m.findings)-i))
+			break
+		}
+		repairScene.WriteString(fmt.Sprintf("   %s\n", f))
+	}
+	repairScene.WriteString("\n")
+	if m.repairKeys.keep.Enabled() {
+		repairScene.WriteString("   keep     leave the data as it is\n")
+	} else {
+		repairScene.WriteString(lipgloss.NewStyle().Foreground(docInactiveColor).Render("   keep     the game in progress cannot be kept"))
+		repairScene.WriteString("\n")
+	}
+	repairScene.WriteString("   repair   rescore from the rolls, keep the rest\n")
+	repairScene.WriteString("   discard  drop the games with problems\n\n")
+	return repairScene.String()
+}
+func (m Model) errorScene() string {
+	errorScene := strings.Builder{}
+	errorScene.WriteString(lipgloss.NewStyle().Foreground(docColor).Render("   Something went wrong."))
+	errorScene.WriteString("\n\n")
+	errorScene.WriteString(lipgloss.NewStyle().Width(60).PaddingLeft(3).Render(m.failure.err.Error()))
+	errorScene.WriteString("\n\n")
+	return errorScene.String()
+}
 func (m Model) modeSelectScene() string {
 	modeSelectScene := strings.Builder{}
 	modeSelectScene.WriteString(fmt.Sprintf("%s\n", m.modeSel.View()))
@@ -891,29 +1835,113 @@ func (m Model) dataGenModeScene() string {
 func (m Model) dataSelModeScene() string {
 	dataSelModeScene := strings.Builder{}
 	dataSelModeScene.WriteString(fmt.Sprintf("%s\n", m.modeSel.View()))
+	if !m.picksFiles() {
+		dataSelModeScene.WriteString(fmt.Sprintf("%s\n", m.playerSel.View()))
+		return dataSelModeScene.String()
+	}
 	dataSelModeScene.WriteString(fmt.Sprintf("%s\n", m.dataSel.View()))
 	return dataSelModeScene.String()
 }
+func (m Model) deckDrawing() string {
+	deckDrawing := strings.Builder{}
+	standing := lipgloss.NewStyle().Foreground(docColor)
+	down := lipgloss.NewStyle().Foreground(docInactiveColor)
+	for i, row := range [][]int{{7, 8, 9, 10}, {4, 5, 6}, {2, 3}, {1}} {
+		deckDrawing.WriteString(strings.Repeat(" ", 2*i+4))
+		for _, pin := range row {
+			pinStr := fmt.Sprintf("%2d  ", pin)
+			if m.deck.Has(pin) {
+				deckDrawing.WriteString(standing.Render(pinStr))
+			} else {
+				deckDrawing.WriteString(down.Render(pinStr))
+			}
+		}
+		deckDrawing.WriteString("\n")
+	}
+	deckDrawing.WriteString(down.Render("    Toggle pins with 1-9 and 0, then press enter."))
+	deckDrawing.WriteString("\n\n")
+	return deckDrawing.String()
+}
+func (m Model) laneDrawing() string {
+	laneDrawing := strings.Builder{}
+	active, editFrame := m.Bowl, m.editFrame
+	for i, bowl := range m.lane {
+		m.Bowl, m.editFrame = bowl, -1
+		if i == m.turn {
+			m.Bowl, m.editFrame = active, editFrame
+			laneDrawing.WriteString(lipgloss.NewStyle().Foreground(docColor).Render(fmt.Sprintf(" ▶ %s", bowl.Name)))
+			laneDrawing.WriteString("\n")
+		} else {
+			laneDrawing.WriteString(fmt.Sprintf("   %s\n", bowl.Name))
+		}
+		laneDrawing.WriteString(m.scoreDrawing())
+	}
+	return laneDrawing.String()
+}
+
+func (m Model) laneModeScene() string {
+	laneModeScene := strings.Builder{}
+	laneModeScene.WriteString(fmt.Sprintf("%s\n", m.modeSel.View()))
+	laneModeScene.WriteString(fmt.Sprintf("%s\n\n", m.laneInput.View()))
+	return laneModeScene.String()
+}
 func (m Model) mgmtScoreScene() string {
 	mgmtScoreScene := strings.Builder{}
-	if len(m.Bowl.Archives) > 0 {
-		mgmtScoreScene.WriteString(m.archivesScoreDrawing())
+	if len(m.lane) > 0 {
+		mgmtScoreScene.WriteString(m.laneDrawing())
+	} else {
+		if len(m.Bowl.Archives) > 0 {
+			mgmtScoreScene.WriteString(m.archivesScoreDrawing())
+		}
+		mgmtScoreScene.WriteString(m.scoreDrawing())
+	}
+	if m.deckMode {
+		mgmtScoreScene.WriteString(m.deckDrawing())
+		return mgmtScoreScene.String()
 	}
-	mgmtScoreScene.WriteString(m.scoreDrawing())
 	mgmtScoreScene.WriteString(fmt.Sprintf("%s\n\n", m.scoreInput.View()))
+	if m.notice != "" {
+		mgmtScoreScene.WriteString(fmt.Sprintf("   %s\n\n", m.notice))
+	}
 	return mgmtScoreScene.String()
 }
 func (m Model) infoLine() (string, string) {
 	name := fmt.Sprintf(" Player: %s\n\n", m.Bowl.Name)
+	var game []string
+	if !m.Bowl.tenPin() {
+		game = append(game, m.Bowl.Discipline)
+	}
+	if rules, err := scoring.LookupRules(m.Bowl.Rules); err == nil && rules != scoring.Traditional {
+		game = append(game, rules.Name())
+	}
+	if variant, err := scoring.LookupVariant(m.Bowl.Variant); err == nil && variant.Name != scoring.Standard.Name {
+		game = append(game, variant.Name)
+	}
+	if len(game) > 0 {
+		name = fmt.Sprintf(" Player: %s  (%s)\n\n", m.Bowl.Name, strings.Join(game, ", "))
+	}
 	switch m.scene {
 	case "modeSelect":
 		m.selectKeys = upDownKeys
-	case "dataGenMode":
+	case "dataGenMode", "laneMode", "statsScene":
 		return name, lipgloss.PlaceHorizontal(40, 1, m.keyHelp.View(m.inputKeys))
-	case "dataSelMode":
+	case "repairScene":
+		return name, lipgloss.PlaceHorizontal(44, 1, m.keyHelp.View(m.repairKeys))
+	case "errorScene":
+		return "", lipgloss.PlaceHorizontal(44, 1, m.keyHelp.View(m.errorKeys))
+	case "dataSelMode", "leagueSelMode":
 		m.selectKeys = upDownKeys
+	case "leagueScene":
+		m.selectKeys = rightLeftKeys
+		return fmt.Sprintf(" League: %s\n\n", m.league.Name), lipgloss.PlaceHorizontal(44, 1, m.keyHelp.View(m.selectKeys))
 	case "mgmtScore":
 		m.selectKeys = rightLeftKeys
+		return name, lipgloss.JoinVertical(
+			lipgloss.Left,
+			lipgloss.PlaceHorizontal(44, 1, m.keyHelp.View(m.selectKeys)),
+			lipgloss.PlaceHorizontal(44, 1, m.keyHelp.View(m.editKeys)),
+			lipgloss.PlaceHorizontal(44, 1, m.keyHelp.View(m.gameKeys)),
+		)
 	}
 	return name, lipgloss.PlaceHorizontal(44, 1, m.keyHelp.View(m.selectKeys))
 }
@@ -928,6 +1956,21 @@ func (m Model) View() string {
 		view.WriteString(m.dataGenModeScene())
 	case "dataSelMode":
 		view.WriteString(m.dataSelModeScene())
+	case "laneMode":
+		view.WriteString(m.laneModeScene())
+	case "leagueSelMode":
+		view.WriteString(m.leagueSelModeScene())
+	case "leagueScene":
+		view.WriteString(name)
+		view.WriteString(m.leagueScene())
+	case "statsScene":
+		view.WriteString(name)
+		view.WriteString(m.statsScene())
+	case "repairScene":
+		view.WriteString(name)
+		view.WriteString(m.repairScene())
+	case "errorScene":
+		view.WriteString(m.errorScene())
 	case "mgmtScore":
 		view.WriteString(name)
 		view.WriteString(m.mgmtScoreScene())
@@ -943,6 +1986,13 @@ func initPins() [21]string {
 	}
 	return pins
 }
+func initMarks() []string {
+	marks := make([]string, scoring.BoxSlots)
+	for i := range marks {
+		marks[i] = "yet"
+	}
+	return marks
+}
 func initScores() [11]int {
 	var scores [11]int
 	for i := range scores {
@@ -957,33 +2007,33 @@ func initScores() [11]int {
 func initBowl() Bowl {
 	var archives []Archive
 	return Bowl{
-		Name:     time.Now().Format("20060102-150405MST"),
-		Pins:     initPins(),
-		Scores:   initScores(),
-		MaxScore: 300,
-		Times:    0,
-		Archives: archives,
+		SchemaVersion: schemaVersion,
+		Name:          time.Now().Format("20060102-150405MST"),
+		Pins:          initPins(),
+		Scores:        initScores(),
+		MaxScore:      300,
+		Times:         0,
+		Archives:      archives,
 	}
 }
-func initLogger() *log.Logger {
-	if _, err := os.Stat("logs"); err != nil {
-		if e := os.Mkdir("logs", 0777); e != nil {
-			fmt.Println("Error running program:", err)
-			os.Exit(1)
-		}
+
+// initLogger logs to a new file in logs/. When that file cannot be created
+// the app runs without a log rather than not at all.
+func initLogger() (*log.Logger, error) {
+	if err := os.MkdirAll("logs", 0777); err != nil {
+		return log.New(io.Discard), fmt.Errorf("no log is kept: %w", err)
 	}
 	timer := time.Now().Format("20060102-150405MST")
 	logfile, err := os.Create(filepath.Join("logs", fmt.Sprintf("%s.log", timer)))
 	if err != nil {
-		fmt.Println("Error running program:", err)
-		os.Exit(1)
+		return log.New(io.Discard), fmt.Errorf("no log is kept: %w", err)
 	}
 	logger := log.NewWithOptions(logfile, log.Options{
 		ReportCaller:    true,
 		ReportTimestamp: true,
 		TimeFormat:      "2006/01/02 15:04:05 -0700 MST",
 	})
-	return logger
+	return logger, nil
 }
 func initKeyHelp() help.Model {
 	keyHelp := help.New()
@@ -993,7 +2043,7 @@ func initKeyHelp() help.Model {
 	return keyHelp
 }
 func initModeSel() list.Model {
-	modeSel := list.New(menu, list.NewDefaultDelegate(), 22, 6)
+	modeSel := list.New(menu, list.NewDefaultDelegate(), 27, 12)
 	modeSel.Title = "Mode selection"
 	modeSel.SetShowTitle(false)
 	modeSel.SetShowHelp(false)
@@ -1003,6 +2053,24 @@ func initModeSel() list.Model {
 	modeSel.Styles.FilterCursor = lipgloss.NewStyle().Foreground(docColor)
 	return modeSel
 }
+func (m Model) initPlayerSel() list.Model {
+	var players []list.Item
+	names, err := m.store.Players()
+	if err != nil {
+		m.logger.Error("Failed to list players.")
+	}
+	for _, name := range names {
+		players = append(players, dish{state: name, desc: "Saved player."})
+	}
+	playerSel := list.New(players, list.NewDefaultDelegate(), 27, 12)
+	playerSel.Title = "Player selection"
+	playerSel.SetShowTitle(false)
+	playerSel.SetShowHelp(false)
+	playerSel.SetShowStatusBar(false)
+	playerSel.SetFilteringEnabled(false)
+	playerSel.Styles.NoItems = lipgloss.NewStyle().Foreground(docInactiveColor).PaddingLeft(3)
+	return playerSel
+}
 func initNameInput() textinput.Model {
 	nameInput := textinput.New()
 	nameInput.CharLimit = 37
@@ -1011,16 +2079,20 @@ func initNameInput() textinput.Model {
 	nameInput.Focus()
 	return nameInput
 }
-func initDataSel() filepicker.Model {
+func initLaneInput() textinput.Model {
+	laneInput := textinput.New()
+	laneInput.CharLimit = 200
+	laneInput.Width = 37
+	laneInput.Placeholder = "Who is bowling? (separate with commas)"
+	laneInput.PlaceholderStyle = lipgloss.NewStyle().Foreground(docInactiveColor)
+	laneInput.Focus()
+	return laneInput
+}
+func initDataSel() (filepicker.Model, error) {
 	dataSel := filepicker.New()
 	dataSel.AllowedTypes = []string{".json"}
 	dir, _ := os.Getwd()
-	if _, err := os.Stat("data"); err != nil {
-		if e := os.Mkdir("data", 0777); e != nil {
-			fmt.Println("Error running program:", e)
-			os.Exit(1)
-		}
-	}
+	err := os.MkdirAll("data", 0777)
 	dataSel.CurrentDirectory = filepath.Join(dir, "data")
 	dataSel.Styles.Selected = lipgloss.NewStyle().Foreground(docColor)
 	dataSel.Styles.EmptyDirectory =
@@ -1028,11 +2100,11 @@ func initDataSel() filepicker.Model {
 			Foreground(docInactiveColor).
 			PaddingLeft(3).
 			SetString("No Files Found.\n")
-	return dataSel
+	return dataSel, err
 }
 func initScoreInput() textinput.Model {
 	scoreInput := textinput.New()
-	scoreInput.CharLimit = 2
+	scoreInput.CharLimit = 40
 	scoreInput.Placeholder = "How many pins were knocked down?"
 	scoreInput.PlaceholderStyle = lipgloss.NewStyle().Foreground(docInactiveColor)
 	scoreInput.Focus()
@@ -1047,23 +2119,76 @@ func initScoreSel() paginator.Model {
 	return scoreSel
 }
 func initModel() tea.Model {
+	logger, err := initLogger()
+	dataSel, dataErr := initDataSel()
+	if err == nil {
+		err = dataErr
+	}
 	return Model{
 		Bowl:       initBowl(),
-		logger:     initLogger(),
+		logger:     logger,
 		inputKeys:  inputKeys,
 		selectKeys: upDownKeys,
 		keyHelp:    initKeyHelp(),
 		scene:      "modeSelect",
 		modeSel:    initModeSel(),
 		nameInput:  initNameInput(),
-		dataSel:    initDataSel(),
+		dataSel:    dataSel,
 		scoreInput: initScoreInput(),
 		scoreSel:   initScoreSel(),
+		editKeys:   editKeys,
+		repairKeys: repairKeys,
+		errorKeys:  errorKeys,
+		failure:    errorMsg{err: err},
+		gameKeys:   gameKeys,
+		editFrame:  -1,
+		lastTurn:   -1,
+		handback:   -1,
+		edit:       record(scoring.New()),
+		deck:       scoring.FullDeck,
+		laneInput:  initLaneInput(),
+		store:      jsonStore{dir: "data"},
+		journal:    &journal{dir: filepath.Join("data", "journal")},
+		leagues:    leagueStore{dir: filepath.Join("data", "league")},
 	}
 }
 
 func main() {
-	if _, err := tea.NewProgram(initModel(), tea.WithAltScreen()).Run(); err != nil {
+	stream := flag.String("stream", "", "stream scores as server-sent events on `address`")
+	db := flag.String("db", "", "keep players in the SQLite database at `path` instead of data/")
+	flag.Parse()
+	model := initModel().(Model)
+	if *db != "" {
+		store, err := openSQLite(*db)
+		if err != nil {
+			fmt.Println("Error running program:", err)
+			os.Exit(1)
+		}
+		model.store = store
+		model.logger.Info(fmt.Sprintf("Use the database \"%s\".", *db))
+	}
+	if flag.NArg() > 0 {
+		if model.failure.err != nil {
+			fmt.Fprintln(os.Stderr, "Warning:", model.failure.err)
+		}
+		os.Exit(model.runCommand(flag.Args()))
+	}
+	if *stream != "" {
+		model.events = newBroker()
+		go func() {
+			if err := http.ListenAndServe(*stream, model.events); err != nil {
+				model.logger.Error(fmt.Sprintf("Failed to stream scores on \"%s\".", *stream))
+			}
+		}()
+		model.logger.Info(fmt.Sprintf("Stream scores on \"%s\".", *stream))
+	}
+	p := tea.NewProgram(model, tea.WithAltScreen(), tea.WithoutSignalHandler())
+	go func() {
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+		p.Send(signalMsg{sig: <-sig})
+	}()
+	if _, err := p.Run(); err != nil {
 		fmt.Println("Error running program:", err)
 		os.Exit(1)
 	}
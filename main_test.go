@@ -0,0 +1,34 @@
+package main
+
+import (
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/charmbracelet/log"
+	"github.com/ryokpen87044/bowlingScorer/scoring"
+)
+
+// testModel is a model that keeps its players, journals and leagues in a
+// temporary directory and logs nowhere.
+func testModel(t *testing.T) Model {
+	t.Helper()
+	dir := t.TempDir()
+	return Model{
+		Bowl:       initBowl(),
+		logger:     log.New(io.Discard),
+		nameInput:  initNameInput(),
+		scoreSel:   initScoreSel(),
+		editKeys:   editKeys,
+		gameKeys:   gameKeys,
+		repairKeys: repairKeys,
+		editFrame:  -1,
+		lastTurn:   -1,
+		handback:   -1,
+		edit:       record(scoring.New()),
+		deck:       scoring.FullDeck,
+		store:      jsonStore{dir: dir},
+		journal:    &journal{dir: filepath.Join(dir, "journal")},
+		leagues:    leagueStore{dir: filepath.Join(dir, "league")},
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// schemaVersion is the version of the player data written by this program.
+const schemaVersion = 1
+
+var errNewerSchema = errors.New("data was written by a newer version")
+
+// migrations upgrade raw player data one version at a time: migrations[i]
+// turns version i into version i+1. Data from before schemaVersion existed is
+// version 0.
+var migrations = []func(data map[string]any){
+	// Version 1 adds schemaVersion itself. Older files may have a null list of
+	// archives or no maximum score yet.
+	func(data map[string]any) {
+		if data["archives"] == nil {
+			data["archives"] = []any{}
+		}
+		if _, ok := data["maxScore"]; !ok {
+			data["maxScore"] = 300
+		}
+	},
+}
+
+// migrate brings raw player data up to schemaVersion. It returns the upgraded
+// data and the version the data had.
+func migrate(raw []byte) ([]byte, int, error) {
+	var data map[string]any
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, 0, err
+	}
+	version := 0
+	if v, ok := data["schemaVersion"].(float64); ok {
+		version = int(v)
+	}
+	if version > schemaVersion {
+		return nil, version, fmt.Errorf("%w: schemaVersion %d", errNewerSchema, version)
+	}
+	if version == schemaVersion {
+		return raw, version, nil
+	}
+	for v := version; v < schemaVersion; v++ {
+		migrations[v](data)
+	}
+	data["schemaVersion"] = schemaVersion
+	upgraded, err := json.MarshalIndent(data, "", "  ")
+	return upgraded, version, err
+}
+
+// upgrade migrates the file of name to schemaVersion, saving it with the
+// original kept as a backup. It returns the version the file had.
+func (s jsonStore) upgrade(name string) (int, error) {
+	raw, err := s.raw(name)
+	if err != nil {
+		return 0, err
+	}
+	upgraded, version, err := migrate(raw)
+	if err != nil || version == schemaVersion {
+		return version, err
+	}
+	var bowl Bowl
+	if err := json.Unmarshal(upgraded, &bowl); err != nil {
+		return version, err
+	}
+	return version, s.Save(bowl)
+}
+
+// backup keeps the file of name next to it as <name>.json.v<version>.bak
+// before a file of an older version is written over. A file that is missing
+// needs no backup; one whose version cannot be told is not written over.
+func (s jsonStore) backup(name string) error {
+	raw, err := s.raw(name)
+	if errors.Is(err, errNoPlayer) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	_, version, err := migrate(raw)
+	if err != nil {
+		return fmt.Errorf("keep %s: %w", s.path(name), err)
+	}
+	if version == schemaVersion {
+		return nil
+	}
+	return os.WriteFile(fmt.Sprintf("%s.v%d.bak", s.path(name), version), raw, 0644)
+}
+func (m Model) migrateCommand(args []string, stdout io.Writer) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+	store, ok := m.store.(jsonStore)
+	if !ok {
+		return errors.New("migrate upgrades the JSON files in data/ and cannot be used with -db")
+	}
+	names, err := store.Players()
+	if err != nil {
+		return err
+	}
+	failed := 0
+	for _, name := range names {
+		version, err := store.upgrade(name)
+		switch {
+		case err != nil:
+			failed++
+			fmt.Fprintf(stdout, "%s: %v\n", name, err)
+		case version < schemaVersion:
+			m.logger.Info(fmt.Sprintf("Migrate data of \"%s\" from version %d.", name, version))
+			fmt.Fprintf(stdout, "%s: upgraded from version %d to %d\n", name, version, schemaVersion)
+		default:
+			fmt.Fprintf(stdout, "%s: up to date\n", name)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d players could not be migrated", failed)
+	}
+	return nil
+}
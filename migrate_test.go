@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const oldPlayer = `{"name":"old","pins":["X","yet","yet","yet","yet","yet","yet","yet","yet","yet","yet","yet","yet","yet","yet","yet","yet","yet","yet","yet","yet"],"scores":[0,-1,-1,-1,-1,-1,-1,-1,-1,-1,-1],"times":2,"archives":null}`
+
+func writePlayer(t *testing.T, m Model, name, data string) string {
+	t.Helper()
+	store := m.store.(jsonStore)
+	if err := os.WriteFile(store.path(name), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return store.path(name)
+}
+
+func TestMigrate(t *testing.T) {
+	upgraded, version, err := migrate([]byte(oldPlayer))
+	if err != nil || version != 0 {
+		t.Fatalf("migrate() = %d, %v", version, err)
+	}
+	for _, want := range []string{`"schemaVersion": 1`, `"maxScore": 300`, `"archives": []`} {
+		if !bytes.Contains(upgraded, []byte(want)) {
+			t.Errorf("migrate() has no %s:\n%s", want, upgraded)
+		}
+	}
+	if _, _, err := migrate([]byte(`{"schemaVersion":99}`)); !errors.Is(err, errNewerSchema) {
+		t.Errorf("migrate() of a newer version = %v, want %v", err, errNewerSchema)
+	}
+}
+
+// Reading a player never changes the folder: the file is upgraded, with a
+// backup, only when it is saved or migrated.
+func TestLoadOldPlayer(t *testing.T) {
+	m := testModel(t)
+	path := writePlayer(t, m, "old", oldPlayer)
+	bowl, err := m.store.Load("old")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bowl.MaxScore != 300 || bowl.Archives == nil {
+		t.Errorf("Load() = %+v, want the upgraded player", bowl)
+	}
+	var out bytes.Buffer
+	m.validateCommand(nil, &out)
+	m.listCommand(nil, &out)
+	m.showCommand([]string{"old"}, &out)
+	if raw, _ := os.ReadFile(path); string(raw) != oldPlayer {
+		t.Errorf("reading rewrote the file:\n%s", raw)
+	}
+	if backups, _ := filepath.Glob(path + ".*.bak"); len(backups) != 0 {
+		t.Errorf("reading left backups %v", backups)
+	}
+
+	if err := m.store.Save(bowl); err != nil {
+		t.Fatal(err)
+	}
+	if raw, _ := os.ReadFile(path + ".v0.bak"); string(raw) != oldPlayer {
+		t.Errorf("backup = %q, want the old file", raw)
+	}
+	if raw, _ := os.ReadFile(path); !bytes.Contains(raw, []byte(`"schemaVersion": 1`)) {
+		t.Errorf("saved file is not upgraded:\n%s", raw)
+	}
+}
+
+func TestMigrateCommand(t *testing.T) {
+	m := testModel(t)
+	path := writePlayer(t, m, "old", oldPlayer)
+	writePlayer(t, m, "new", `{"schemaVersion":99,"name":"new"}`)
+	var out bytes.Buffer
+	if err := m.migrateCommand(nil, &out); err == nil {
+		t.Error("migrateCommand() with a newer player = nil error")
+	}
+	if !strings.Contains(out.String(), "old: upgraded from version 0 to 1") {
+		t.Errorf("migrate output:\n%s", out.String())
+	}
+	if _, err := os.Stat(path + ".v0.bak"); err != nil {
+		t.Error(err)
+	}
+	out.Reset()
+	os.Remove(m.store.(jsonStore).path("new"))
+	if err := m.migrateCommand(nil, &out); err != nil || out.String() != "old: up to date\n" {
+		t.Errorf("migrate again = %v:\n%s", err, out.String())
+	}
+
+	m.store = sqliteStore{}
+	if err := m.migrateCommand(nil, &out); err == nil {
+		t.Error("migrateCommand() under -db = nil error")
+	}
+}
+
+func TestSaveOverNewerPlayer(t *testing.T) {
+	m := testModel(t)
+	newer := `{"schemaVersion":99,"name":"new"}`
+	path := writePlayer(t, m, "new", newer)
+	if _, err := m.store.Load("new"); !errors.Is(err, errNewerSchema) {
+		t.Errorf("Load() = %v, want %v", err, errNewerSchema)
+	}
+	bowl := initBowl()
+	bowl.Name = "new"
+	if err := m.store.Save(bowl); !errors.Is(err, errNewerSchema) {
+		t.Errorf("Save() over a newer file = %v, want %v", err, errNewerSchema)
+	}
+	if raw, _ := os.ReadFile(path); string(raw) != newer {
+		t.Errorf("Save() wrote over a newer file:\n%s", raw)
+	}
+}
@@ -0,0 +1,338 @@
+package scoring
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// BoxSlots is the length of the layout of a three-ball game: three slots for
+// every frame, the tenth included.
+const BoxSlots = 3 * NumFrames
+
+var ErrUnknownDiscipline = errors.New("scoring: unknown discipline")
+
+// Discipline describes a bowling game played with up to three balls a frame.
+// PinValues holds what each pin is worth. A strike scores the rack plus the
+// next two balls, a spare (cleared with the second ball) the rack plus the
+// next ball, and any other frame the pins it knocked down. The tenth frame
+// always has three balls.
+//
+// Candlepin leaves the fallen wood on the deck between balls and duckpin
+// clears it, which changes how the pins fall but not how they are scored.
+type Discipline struct {
+	Name      string
+	PinValues []int
+}
+
+var (
+	Candlepin = Discipline{Name: "candlepin", PinValues: []int{1, 1, 1, 1, 1, 1, 1, 1, 1, 1}}
+	Duckpin   = Discipline{Name: "duckpin", PinValues: []int{1, 1, 1, 1, 1, 1, 1, 1, 1, 1}}
+	FivePin   = Discipline{Name: "five-pin", PinValues: []int{2, 3, 5, 3, 2}}
+)
+
+var disciplines = []Discipline{Candlepin, Duckpin, FivePin}
+
+// Disciplines returns every three-ball discipline.
+func Disciplines() []Discipline {
+	return append([]Discipline(nil), disciplines...)
+}
+
+func LookupDiscipline(name string) (Discipline, error) {
+	for _, d := range disciplines {
+		if d.Name == name {
+			return d, nil
+		}
+	}
+	return Discipline{}, fmt.Errorf("%w: %q", ErrUnknownDiscipline, name)
+}
+
+// Rack returns the value of a full rack.
+func (d Discipline) Rack() int {
+	rack := 0
+	for _, v := range d.PinValues {
+		rack += v
+	}
+	return rack
+}
+
+// Max returns a perfect game.
+func (d Discipline) Max() int {
+	return 3 * d.Rack() * NumFrames
+}
+
+func (d Discipline) smallest() int {
+	small := d.Rack()
+	for _, v := range d.PinValues {
+		if v < small {
+			small = v
+		}
+	}
+	return small
+}
+
+// BoxGame is a single game of a three-ball discipline. Balls hold the value
+// of the pins knocked down rather than a pin count.
+type BoxGame struct {
+	discipline Discipline
+	balls      []int
+}
+
+func NewBox(d Discipline) *BoxGame {
+	return &BoxGame{discipline: d}
+}
+
+// BoxFromMarks rebuilds a game from its layout of BoxSlots marks.
+func BoxFromMarks(d Discipline, marks []string) (*BoxGame, error) {
+	g := NewBox(d)
+	if marks == nil {
+		return g, nil
+	}
+	if len(marks) != BoxSlots {
+		return nil, fmt.Errorf("%w: %d marks", ErrInvalidRoll, len(marks))
+	}
+	for slot := 0; slot < BoxSlots && !g.Complete(); slot++ {
+		if g.Times() != slot {
+			continue
+		}
+		if marks[slot] == "yet" {
+			break
+		}
+		if err := g.mark(marks[slot]); err != nil {
+			return nil, &RollError{Roll: slot + 1, Mark: marks[slot], Err: err}
+		}
+	}
+	got := g.Marks()
+	for i := range marks {
+		if got[i] != marks[i] {
+			return nil, &RollError{Roll: i + 1, Mark: marks[i], Err: ErrInvalidRoll}
+		}
+	}
+	return g, nil
+}
+
+func (g *BoxGame) mark(s string) error {
+	switch s {
+	case "X":
+		if !g.FullRack() {
+			return ErrInvalidRoll
+		}
+		return g.Roll(g.Standing())
+	case "/":
+		if g.FullRack() {
+			return ErrInvalidRoll
+		}
+		return g.Roll(g.Standing())
+	case "-":
+		return g.Roll(0)
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return ErrInvalidRoll
+	}
+	return g.Roll(n)
+}
+
+func (g *BoxGame) Discipline() Discipline {
+	return g.discipline
+}
+
+// Roll records a ball by the value of the pins it knocked down. For pins worth
+// more than one, a count that would take or leave less than the smallest pin
+// is rejected.
+func (g *BoxGame) Roll(value int) error {
+	if g.Complete() {
+		return ErrGameOver
+	}
+	standing := g.Standing()
+	small := g.discipline.smallest()
+	if value < 0 || value > standing || value > 0 && value < small || standing-value > 0 && standing-value < small {
+		return ErrInvalidRoll
+	}
+	g.balls = append(g.balls, value)
+	return nil
+}
+
+func (g *BoxGame) Undo() error {
+	if len(g.balls) == 0 {
+		return ErrNoRolls
+	}
+	g.balls = g.balls[:len(g.balls)-1]
+	return nil
+}
+
+func (g *BoxGame) frames() [][]int {
+	var frames [][]int
+	start := 0
+	for f := 0; f < NumFrames && start < len(g.balls); f++ {
+		end := start + 1
+		for end < len(g.balls) && !g.frameComplete(f, g.balls[start:end]) {
+			end++
+		}
+		frames = append(frames, g.balls[start:end])
+		start = end
+	}
+	return frames
+}
+
+func (g *BoxGame) frameComplete(f int, balls []int) bool {
+	if len(balls) == 3 {
+		return true
+	}
+	down := 0
+	for _, b := range balls {
+		down += b
+	}
+	return f < NumFrames-1 && down == g.discipline.Rack()
+}
+
+// standingAfter returns the value standing after balls, resetting the rack
+// whenever it is cleared.
+func (g *BoxGame) standingAfter(balls []int) int {
+	standing := g.discipline.Rack()
+	for _, b := range balls {
+		standing -= b
+		if standing == 0 {
+			standing = g.discipline.Rack()
+		}
+	}
+	return standing
+}
+
+func (g *BoxGame) current() (int, []int) {
+	frames := g.frames()
+	if len(frames) == 0 {
+		return 0, nil
+	}
+	f := len(frames) - 1
+	if g.frameComplete(f, frames[f]) {
+		return f + 1, nil
+	}
+	return f, frames[f]
+}
+
+func (g *BoxGame) Complete() bool {
+	frames := g.frames()
+	return len(frames) == NumFrames && g.frameComplete(NumFrames-1, frames[NumFrames-1])
+}
+
+func (g *BoxGame) Standing() int {
+	if g.Complete() {
+		return 0
+	}
+	_, balls := g.current()
+	return g.standingAfter(balls)
+}
+
+func (g *BoxGame) FullRack() bool {
+	return !g.Complete() && g.Standing() == g.discipline.Rack()
+}
+
+// Frames returns the frames rolled so far. Each ball's Pins is the value it
+// knocked down.
+func (g *BoxGame) Frames() []Frame {
+	var frames []Frame
+	rack := g.discipline.Rack()
+	total := 0
+	scored := true
+	start := 0
+	for f, balls := range g.frames() {
+		frame := Frame{Complete: g.frameComplete(f, balls)}
+		for _, b := range balls {
+			frame.Balls = append(frame.Balls, Ball{Pins: b})
+		}
+		if scored && frame.Complete {
+			// A strike or spare counts the balls after it up to three in all.
+			count := len(balls)
+			if balls[0] == rack || len(balls) > 1 && balls[0]+balls[1] == rack {
+				count = 3
+			}
+			if start+count <= len(g.balls) {
+				for _, b := range g.balls[start : start+count] {
+					total += b
+				}
+				frame.Score = total
+				frame.Scored = true
+			}
+		}
+		scored = frame.Scored
+		frames = append(frames, frame)
+		start += len(balls)
+	}
+	return frames
+}
+
+func (g *BoxGame) Total() int {
+	total := 0
+	for _, frame := range g.Frames() {
+		if frame.Scored {
+			total = frame.Score
+		}
+	}
+	return total
+}
+
+func (g *BoxGame) MaxPossible() int {
+	best := &BoxGame{discipline: g.discipline, balls: append([]int(nil), g.balls...)}
+	for !best.Complete() {
+		best.Roll(best.Standing())
+	}
+	return best.Total()
+}
+
+// Marks returns the game in its layout of BoxSlots marks, three per frame,
+// with "yet" for balls not rolled.
+func (g *BoxGame) Marks() []string {
+	marks := make([]string, BoxSlots)
+	for i := range marks {
+		marks[i] = "yet"
+	}
+	rack := g.discipline.Rack()
+	for f, balls := range g.frames() {
+		standing := rack
+		onRack := 0
+		for j, b := range balls {
+			switch {
+			case b == standing && onRack == 0:
+				marks[3*f+j] = "X"
+			case b == standing && onRack == 1:
+				marks[3*f+j] = "/"
+			case b == 0:
+				marks[3*f+j] = "-"
+			default:
+				marks[3*f+j] = strconv.Itoa(b)
+			}
+			standing -= b
+			onRack++
+			if standing == 0 {
+				standing = rack
+				onRack = 0
+			}
+		}
+	}
+	return marks
+}
+
+// Scores returns the running totals in the same 11-slot layout as Game.
+func (g *BoxGame) Scores() [NumFrames + 1]int {
+	var scores [NumFrames + 1]int
+	for i := 1; i < len(scores); i++ {
+		scores[i] = -1
+	}
+	for f, frame := range g.Frames() {
+		if frame.Scored {
+			scores[f+1] = frame.Score
+		}
+	}
+	return scores
+}
+
+// Times returns the index of the next slot in the layout, or BoxSlots once
+// the game is over.
+func (g *BoxGame) Times() int {
+	if g.Complete() {
+		return BoxSlots
+	}
+	f, balls := g.current()
+	return 3*f + len(balls)
+}
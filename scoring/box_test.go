@@ -0,0 +1,54 @@
+package scoring
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBox(t *testing.T) {
+	for _, tt := range []struct {
+		discipline Discipline
+		max        int
+	}{
+		{FivePin, 450},
+		{Candlepin, 300},
+		{Duckpin, 300},
+	} {
+		t.Run(tt.discipline.Name, func(t *testing.T) {
+			if got := tt.discipline.Max(); got != tt.max {
+				t.Errorf("Max() = %d, want %d", got, tt.max)
+			}
+			g := NewBox(tt.discipline)
+			for !g.Complete() {
+				if err := g.Roll(g.Standing()); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := g.Total(); got != tt.max {
+				t.Errorf("Total() = %d, want %d", got, tt.max)
+			}
+			again, err := BoxFromMarks(tt.discipline, g.Marks())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if again.Scores() != g.Scores() {
+				t.Errorf("BoxFromMarks(Marks()) scores %v, want %v", again.Scores(), g.Scores())
+			}
+		})
+	}
+	g := NewBox(FivePin)
+	for _, value := range []int{1, 14} {
+		if err := g.Roll(value); !errors.Is(err, ErrInvalidRoll) {
+			t.Errorf("five-pin Roll(%d) = %v, want %v", value, err, ErrInvalidRoll)
+		}
+	}
+	g.Roll(5)
+	g.Roll(5)
+	g.Roll(3)
+	if got := g.Scores()[1]; got != 13 {
+		t.Errorf("five-pin frame of 5 5 3 = %d, want 13", got)
+	}
+	if _, err := LookupDiscipline("bocce"); !errors.Is(err, ErrUnknownDiscipline) {
+		t.Errorf("LookupDiscipline(bocce) = %v, want %v", err, ErrUnknownDiscipline)
+	}
+}
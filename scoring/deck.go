@@ -0,0 +1,96 @@
+package scoring
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+var ErrInvalidLeave = errors.New("scoring: invalid leave")
+
+// Deck is a set of pins, numbered 1 to 10 from the headpin back.
+type Deck uint16
+
+const FullDeck Deck = 1<<NumPins - 1
+
+func DeckOf(pins ...int) (Deck, error) {
+	var d Deck
+	for _, pin := range pins {
+		if pin < 1 || pin > NumPins {
+			return 0, fmt.Errorf("%w: no pin %d", ErrInvalidLeave, pin)
+		}
+		d |= 1 << (pin - 1)
+	}
+	return d, nil
+}
+
+func (d Deck) Has(pin int) bool {
+	return pin >= 1 && pin <= NumPins && d&(1<<(pin-1)) != 0
+}
+
+func (d Deck) Toggle(pin int) Deck {
+	if pin < 1 || pin > NumPins {
+		return d
+	}
+	return d ^ 1<<(pin-1)
+}
+
+func (d Deck) Count() int {
+	return bits.OnesCount16(uint16(d & FullDeck))
+}
+
+// Pins returns the pins in the set in ascending order. It never returns nil so
+// that a cleared deck is still recorded as a leave.
+func (d Deck) Pins() []int {
+	pins := []int{}
+	for pin := 1; pin <= NumPins; pin++ {
+		if d.Has(pin) {
+			pins = append(pins, pin)
+		}
+	}
+	return pins
+}
+
+// Deck returns the pins standing for the next ball. The second result is false
+// when the previous ball was recorded as a count only.
+func (g *Game) Deck() (Deck, bool) {
+	if g.Complete() {
+		return 0, true
+	}
+	if g.FullRack() {
+		return FullDeck, true
+	}
+	last := g.balls[len(g.balls)-1]
+	return last.Leave, last.Tracked
+}
+
+// RollLeave rolls a ball recorded by the pins left standing after it. The
+// count is derived from the pins that were standing before the ball.
+func (g *Game) RollLeave(leave Deck) error {
+	if g.Complete() {
+		return ErrGameOver
+	}
+	before, known := g.Deck()
+	if known && leave&^before != 0 || leave.Count() > g.Standing() {
+		return ErrInvalidLeave
+	}
+	return g.roll(Ball{Pins: g.Standing() - leave.Count(), Leave: leave, Tracked: true})
+}
+
+// Leaves returns the standing pins after each ball in the 21-slot layout, or
+// nil when no ball in the game was recorded pin by pin.
+func (g *Game) Leaves() [][]int {
+	var leaves [][]int
+	for f, balls := range g.frames() {
+		for j, b := range balls {
+			if !b.Tracked {
+				continue
+			}
+			if leaves == nil {
+				leaves = make([][]int, NumSlots)
+			}
+			leaves[2*f+j] = b.Leave.Pins()
+		}
+	}
+	return leaves
+}
@@ -0,0 +1,78 @@
+package scoring
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDeck(t *testing.T) {
+	d, err := DeckOf(1, 2, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Count() != 3 || !d.Has(4) || d.Has(3) || d.Has(11) {
+		t.Errorf("DeckOf(1, 2, 4) = %v", d.Pins())
+	}
+	if got := d.Toggle(2).Toggle(10).Pins(); !reflect.DeepEqual(got, []int{1, 4, 10}) {
+		t.Errorf("Toggle(2).Toggle(10).Pins() = %v, want [1 4 10]", got)
+	}
+	if d.Toggle(0) != d || d.Toggle(11) != d {
+		t.Error("Toggle() changed the deck for a pin that does not exist")
+	}
+	if got := Deck(0).Pins(); got == nil || len(got) != 0 {
+		t.Errorf("Pins() of a cleared deck = %#v, want an empty leave", got)
+	}
+	if FullDeck.Count() != NumPins {
+		t.Errorf("FullDeck.Count() = %d, want %d", FullDeck.Count(), NumPins)
+	}
+	if _, err := DeckOf(0); !errors.Is(err, ErrInvalidLeave) {
+		t.Errorf("DeckOf(0) = %v, want %v", err, ErrInvalidLeave)
+	}
+}
+
+func TestRollLeave(t *testing.T) {
+	g := New()
+	if g.Leaves() != nil {
+		t.Errorf("Leaves() of a new game = %v, want nil", g.Leaves())
+	}
+	if d, known := g.Deck(); d != FullDeck || !known {
+		t.Errorf("Deck() of a new game = %v, %t", d.Pins(), known)
+	}
+	leave, _ := DeckOf(7, 10)
+	if err := g.RollLeave(leave); err != nil {
+		t.Fatal(err)
+	}
+	if d, known := g.Deck(); d != leave || !known {
+		t.Errorf("Deck() after 7-10 = %v, %t", d.Pins(), known)
+	}
+	headpin, _ := DeckOf(1)
+	if err := g.RollLeave(headpin); !errors.Is(err, ErrInvalidLeave) {
+		t.Errorf("leaving a pin that was down = %v, want %v", err, ErrInvalidLeave)
+	}
+	if err := g.RollLeave(0); err != nil {
+		t.Fatal(err)
+	}
+
+	// A count tells the number of pins but not which are left.
+	if err := g.Roll(7); err != nil {
+		t.Fatal(err)
+	}
+	if _, known := g.Deck(); known {
+		t.Error("Deck() is known after a count")
+	}
+	four, _ := DeckOf(1, 2, 3, 4)
+	if err := g.RollLeave(four); !errors.Is(err, ErrInvalidLeave) {
+		t.Errorf("leaving 4 of 3 pins = %v, want %v", err, ErrInvalidLeave)
+	}
+	if err := g.RollLeave(headpin); err != nil {
+		t.Fatal(err)
+	}
+	want := [][]int{{7, 10}, {}, nil, {1}}
+	if got := g.Leaves()[:4]; !reflect.DeepEqual(got, want) {
+		t.Errorf("Leaves() = %#v, want %#v", got, want)
+	}
+	if got := g.Scores()[2]; got != 26 {
+		t.Errorf("Scores()[2] = %d, want 26", got)
+	}
+}
@@ -0,0 +1,462 @@
+// Package scoring implements the ten-pin bowling rules used by bowlingScorer.
+package scoring
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+const (
+	NumFrames = 10
+	NumPins   = 10
+	NumSlots  = 21
+)
+
+var (
+	ErrGameOver    = errors.New("scoring: the game is already over")
+	ErrInvalidRoll = errors.New("scoring: invalid number of pins")
+	ErrNoRolls     = errors.New("scoring: no rolls to undo")
+	ErrNoFrame     = errors.New("scoring: no such frame")
+)
+
+// RollError reports the first roll of a stored game that cannot be replayed.
+// Roll counts from 1.
+type RollError struct {
+	Roll int
+	Mark string
+	Err  error
+}
+
+func (e *RollError) Error() string {
+	return fmt.Sprintf("%v: %q at roll %d", e.Err, e.Mark, e.Roll)
+}
+func (e *RollError) Unwrap() error {
+	return e.Err
+}
+
+// Ball is a single delivery. A foul counts as zero pins. Leave holds the pins
+// left standing when the ball was recorded pin by pin (Tracked). NoTap and
+// Free mark strikes granted by the game variant; both count ten pins.
+type Ball struct {
+	Pins    int
+	Foul    bool
+	Leave   Deck
+	Tracked bool
+	NoTap   bool
+	Free    bool
+}
+
+// Frame is one frame of a game. Score is the running total through the frame
+// and is only meaningful when Scored is true.
+type Frame struct {
+	Balls    []Ball
+	Complete bool
+	Score    int
+	Scored   bool
+}
+
+// Game is a single ten-pin game.
+type Game struct {
+	balls   []Ball
+	rules   Rules
+	variant Variant
+}
+
+// Option configures a new game.
+type Option func(*Game)
+
+func WithRules(r Rules) Option {
+	return func(g *Game) {
+		g.rules = r
+	}
+}
+
+func New(opts ...Option) *Game {
+	g := &Game{rules: Traditional, variant: Standard}
+	for _, opt := range opts {
+		opt(g)
+	}
+	g.fill()
+	return g
+}
+
+// FromPins rebuilds a game from the 21-slot layout stored in the data files.
+func FromPins(pins [NumSlots]string, opts ...Option) (*Game, error) {
+	return FromRecord(pins, nil, opts...)
+}
+
+// FromRecord rebuilds a game from the 21-slot layout together with the
+// standing pins recorded after each ball. Slots without a leave, and a nil
+// leaves slice, fall back to the counts in pins.
+func FromRecord(pins [NumSlots]string, leaves [][]int, opts ...Option) (*Game, error) {
+	g := New(opts...)
+	for slot := 0; slot < NumSlots && !g.Complete(); slot++ {
+		if g.Times() != slot {
+			continue
+		}
+		if pins[slot] == "yet" {
+			break
+		}
+		if err := g.record(slot, pins[slot], leaveAt(leaves, slot)); err != nil {
+			return nil, &RollError{Roll: slot + 1, Mark: pins[slot], Err: err}
+		}
+	}
+	got := g.Pins()
+	for i := range pins {
+		if got[i] != pins[i] {
+			return nil, &RollError{Roll: i + 1, Mark: pins[i], Err: ErrInvalidRoll}
+		}
+	}
+	return g, nil
+}
+
+func leaveAt(leaves [][]int, slot int) []int {
+	if slot < len(leaves) {
+		return leaves[slot]
+	}
+	return nil
+}
+
+func (g *Game) record(slot int, mark string, leave []int) error {
+	if leave == nil || mark == "F" {
+		return g.mark(mark)
+	}
+	d, err := DeckOf(leave...)
+	if err != nil {
+		return err
+	}
+	if err := g.RollLeave(d); err != nil {
+		return err
+	}
+	if g.Pins()[slot] != mark {
+		g.balls = g.balls[:len(g.balls)-1]
+		return ErrInvalidLeave
+	}
+	return nil
+}
+
+func (g *Game) mark(s string) error {
+	standing := g.Standing()
+	switch s {
+	case "X":
+		if !g.FullRack() {
+			return ErrInvalidRoll
+		}
+		return g.Roll(NumPins)
+	case "/":
+		if g.FullRack() {
+			return ErrInvalidRoll
+		}
+		return g.Roll(standing)
+	case "G", "-":
+		return g.Roll(0)
+	case "F":
+		return g.Foul()
+	case "N":
+		if !g.FullRack() || g.variant.NoTap == 0 {
+			return ErrInvalidRoll
+		}
+		return g.Roll(g.variant.NoTap)
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n >= standing {
+		return ErrInvalidRoll
+	}
+	return g.Roll(n)
+}
+
+func (g *Game) Roll(pins int) error {
+	return g.roll(Ball{Pins: pins})
+}
+
+func (g *Game) Foul() error {
+	return g.roll(Ball{Foul: true})
+}
+
+func (g *Game) roll(b Ball) error {
+	if g.Complete() {
+		return ErrGameOver
+	}
+	if b.Pins < 0 || b.Pins > g.Standing() {
+		return ErrInvalidRoll
+	}
+	if g.variant.NoTap > 0 && g.FullRack() && b.Pins >= g.variant.NoTap && b.Pins < NumPins {
+		b.Pins, b.NoTap = NumPins, true
+	}
+	g.balls = append(g.balls, b)
+	g.fill()
+	return nil
+}
+
+// Undo takes back the last ball, along with any free strikes that followed it.
+func (g *Game) Undo() error {
+	last := len(g.balls) - 1
+	for last >= 0 && g.balls[last].Free {
+		last--
+	}
+	if last < 0 {
+		return ErrNoRolls
+	}
+	g.balls = g.balls[:last]
+	g.fill()
+	return nil
+}
+
+// Rewind returns a copy of the game holding only the frames before frame.
+func (g *Game) Rewind(frame int) *Game {
+	rewound := g.empty()
+	for f, balls := range g.frames() {
+		if f >= frame {
+			break
+		}
+		rewound.balls = append(rewound.balls, balls...)
+	}
+	rewound.fill()
+	return rewound
+}
+
+// ReplaceFrame re-enters frame with balls and replays every later frame on top
+// of it. The game is left untouched if the result is not a valid game.
+func (g *Game) ReplaceFrame(frame int, balls []Ball) error {
+	frames := g.frames()
+	if frame < 0 || frame >= len(frames) {
+		return ErrNoFrame
+	}
+	replaced := g.Rewind(frame)
+	for _, b := range balls {
+		if b.Free {
+			continue
+		}
+		if err := replaced.roll(b); err != nil {
+			return err
+		}
+	}
+	for _, later := range frames[frame+1:] {
+		if frames := replaced.frames(); len(frames) <= frame || !replaced.frameComplete(frame, frames[frame]) {
+			return ErrInvalidRoll
+		}
+		for _, b := range later {
+			if b.Free {
+				continue
+			}
+			if err := replaced.roll(b); err != nil {
+				return err
+			}
+		}
+		frame++
+	}
+	g.balls = replaced.balls
+	return nil
+}
+
+func (g *Game) empty() *Game {
+	return &Game{rules: g.rules, variant: g.variant}
+}
+
+func (g *Game) Rules() Rules {
+	return g.rules
+}
+
+// Balls returns a copy of every delivery rolled so far.
+func (g *Game) Balls() []Ball {
+	return append([]Ball(nil), g.balls...)
+}
+
+func (g *Game) frames() [][]Ball {
+	var frames [][]Ball
+	start := 0
+	for f := 0; f < NumFrames && start < len(g.balls); f++ {
+		end := start + 1
+		for end < len(g.balls) && !g.frameComplete(f, g.balls[start:end]) {
+			end++
+		}
+		frames = append(frames, g.balls[start:end])
+		start = end
+	}
+	return frames
+}
+
+func (g *Game) frameComplete(f int, balls []Ball) bool {
+	if f < NumFrames-1 || !g.rules.FillBalls() {
+		return len(balls) == 2 || len(balls) == 1 && balls[0].Pins == NumPins
+	}
+	switch len(balls) {
+	case 3:
+		return true
+	case 2:
+		return balls[0].Pins+balls[1].Pins < NumPins
+	}
+	return false
+}
+
+func standingBefore(f int, balls []Ball) int {
+	switch len(balls) {
+	case 0:
+		return NumPins
+	case 1:
+		if f == NumFrames-1 && balls[0].Pins == NumPins {
+			return NumPins
+		}
+		return NumPins - balls[0].Pins
+	}
+	if balls[0].Pins == NumPins && balls[1].Pins < NumPins {
+		return NumPins - balls[1].Pins
+	}
+	return NumPins
+}
+
+func (g *Game) current() (int, []Ball) {
+	frames := g.frames()
+	if len(frames) == 0 {
+		return 0, nil
+	}
+	f := len(frames) - 1
+	if g.frameComplete(f, frames[f]) {
+		return f + 1, nil
+	}
+	return f, frames[f]
+}
+
+// Standing returns the number of pins standing for the next ball.
+func (g *Game) Standing() int {
+	if g.Complete() {
+		return 0
+	}
+	return standingBefore(g.current())
+}
+
+// FullRack reports whether the next ball is rolled at a full, freshly set rack.
+func (g *Game) FullRack() bool {
+	return fresh(g.current())
+}
+
+func (g *Game) Complete() bool {
+	frames := g.frames()
+	return len(frames) == NumFrames && g.frameComplete(NumFrames-1, frames[NumFrames-1])
+}
+
+// Frames returns the frames rolled so far, including the one in progress.
+func (g *Game) Frames() []Frame {
+	var frames []Frame
+	total := 0
+	scored := true
+	start := 0
+	for f, balls := range g.frames() {
+		frame := Frame{
+			Balls:    append([]Ball(nil), balls...),
+			Complete: g.frameComplete(f, balls),
+		}
+		if scored && frame.Complete {
+			n, ok := g.rules.FrameScore(g.balls, start, balls)
+			total += n
+			frame.Score = total
+			frame.Scored = ok
+		}
+		scored = frame.Scored
+		frames = append(frames, frame)
+		start += len(balls)
+	}
+	return frames
+}
+
+// Total returns the running total through the last scored frame.
+func (g *Game) Total() int {
+	total := 0
+	for _, frame := range g.Frames() {
+		if frame.Scored {
+			total = frame.Score
+		}
+	}
+	return total
+}
+
+// MaxPossible returns the score reached if every remaining ball clears the deck.
+func (g *Game) MaxPossible() int {
+	best := g.empty()
+	best.balls = g.Balls()
+	for !best.Complete() {
+		best.Roll(best.Standing())
+	}
+	return best.Total()
+}
+
+// Pins returns the game in the 21-slot layout stored in the data files.
+func (g *Game) Pins() [NumSlots]string {
+	var pins [NumSlots]string
+	for i := range pins {
+		pins[i] = "yet"
+	}
+	for f, balls := range g.frames() {
+		for j, b := range balls {
+			pins[2*f+j] = markOf(f, balls[:j], b)
+		}
+	}
+	return pins
+}
+
+func markOf(f int, before []Ball, b Ball) string {
+	standing := standingBefore(f, before)
+	switch {
+	case b.Foul:
+		return "F"
+	case b.NoTap:
+		return "N"
+	case fresh(f, before):
+		switch b.Pins {
+		case NumPins:
+			return "X"
+		case 0:
+			return "G"
+		}
+	default:
+		switch b.Pins {
+		case standing:
+			return "/"
+		case 0:
+			return "-"
+		}
+	}
+	return strconv.Itoa(b.Pins)
+}
+
+func fresh(f int, before []Ball) bool {
+	switch len(before) {
+	case 0:
+		return true
+	case 1:
+		return f == NumFrames-1 && before[0].Pins == NumPins
+	}
+	if f < NumFrames-1 {
+		return false
+	}
+	if before[0].Pins == NumPins {
+		return before[1].Pins == NumPins
+	}
+	return before[0].Pins+before[1].Pins == NumPins
+}
+
+// Scores returns the running totals in the 11-slot layout stored in the data
+// files: slot 0 is always 0 and unscored frames are -1.
+func (g *Game) Scores() [NumFrames + 1]int {
+	var scores [NumFrames + 1]int
+	for i := 1; i < len(scores); i++ {
+		scores[i] = -1
+	}
+	for f, frame := range g.Frames() {
+		if frame.Scored {
+			scores[f+1] = frame.Score
+		}
+	}
+	return scores
+}
+
+// Times returns the index of the next slot in the 21-slot layout, or NumSlots
+// once the game is over.
+func (g *Game) Times() int {
+	if g.Complete() {
+		return NumSlots
+	}
+	f, balls := g.current()
+	return 2*f + len(balls)
+}
@@ -0,0 +1,152 @@
+package scoring
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func layout(marks string) [NumSlots]string {
+	var pins [NumSlots]string
+	for i := range pins {
+		pins[i] = "yet"
+	}
+	copy(pins[:], strings.Fields(marks))
+	return pins
+}
+
+func TestTotal(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		notation string
+		rules    Rules
+		total    int
+	}{
+		{"perfect game", "X X X X X X X X X XXX", Traditional, 300},
+		{"all spares", "5/ 5/ 5/ 5/ 5/ 5/ 5/ 5/ 5/ 5/5", Traditional, 150},
+		{"mixed", "X 9/ 8- X X 7/ 9- X X X9/", Traditional, 200},
+		{"gutter game", "G- G- G- G- G- G- G- G- G- G-", Traditional, 0},
+		{"all nines", "9- 9- 9- 9- 9- 9- 9- 9- 9- 9-", Traditional, 90},
+		{"current-frame perfect game", "X X X X X X X X X X", CurrentFrame, 300},
+		{"current-frame spares", "9/ 9/ 9/ 9/ 9/ 9/ 9/ 9/ 9/ 9/", CurrentFrame, 190},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := ParseNotation(tt.notation, WithRules(tt.rules))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !g.Complete() {
+				t.Fatal("game is not complete")
+			}
+			if got := g.Total(); got != tt.total {
+				t.Errorf("Total() = %d, want %d", got, tt.total)
+			}
+			if got := g.Notation(); got != tt.notation {
+				t.Errorf("Notation() = %q, want %q", got, tt.notation)
+			}
+		})
+	}
+}
+
+func TestScores(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		notation string
+		rules    Rules
+		scores   [NumFrames + 1]int
+		max      int
+	}{
+		{"strike waits for two balls", "X", Traditional, [11]int{0, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1}, 300},
+		{"spare waits for one ball", "X 9/ 8", Traditional, [11]int{0, 20, 38, -1, -1, -1, -1, -1, -1, -1, -1}, 268},
+		{"current-frame strike is 30 at once", "X", CurrentFrame, [11]int{0, 30, -1, -1, -1, -1, -1, -1, -1, -1, -1}, 300},
+		{"current-frame spare adds the next first ball", "X 7/ 4", CurrentFrame, [11]int{0, 30, 47, -1, -1, -1, -1, -1, -1, -1, -1}, 271},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := ParseNotation(tt.notation, WithRules(tt.rules))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := g.Scores(); got != tt.scores {
+				t.Errorf("Scores() = %v, want %v", got, tt.scores)
+			}
+			if got := g.MaxPossible(); got != tt.max {
+				t.Errorf("MaxPossible() = %d, want %d", got, tt.max)
+			}
+		})
+	}
+}
+
+func TestInvalidRolls(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		notation string
+		err      error
+	}{
+		{"eleven pins", "56", ErrInvalidRoll},
+		{"strike on a second ball", "5X", ErrInvalidRoll},
+		{"spare on a first ball", "/", ErrInvalidRoll},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseNotation(tt.notation); !errors.Is(err, tt.err) {
+				t.Errorf("ParseNotation(%q) = %v, want %v", tt.notation, err, tt.err)
+			}
+		})
+	}
+	g := New()
+	if err := g.Undo(); !errors.Is(err, ErrNoRolls) {
+		t.Errorf("Undo() on a new game = %v, want %v", err, ErrNoRolls)
+	}
+}
+
+func TestFromPins(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		marks string
+		total int
+	}{
+		{"old gutter and miss marks", "G - G 7 F / X yet 3 4", 51},
+		{"foul on a full rack", "F 9 X yet", 9},
+		{"complete game", "X yet X yet X yet X yet X yet X yet X yet X yet X yet X X X", 300},
+		{"tenth frame spare", "G - G - G - G - G - G - G - G - G - 9 / X", 20},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			pins := layout(tt.marks)
+			g, err := FromPins(pins)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := g.Pins(); got != pins {
+				t.Errorf("Pins() = %v, want %v", got, pins)
+			}
+			if got := g.Total(); got != tt.total {
+				t.Errorf("Total() = %d, want %d", got, tt.total)
+			}
+		})
+	}
+	var rollErr *RollError
+	if _, err := FromPins(layout("5 X")); !errors.As(err, &rollErr) || rollErr.Roll != 2 || rollErr.Mark != "X" {
+		t.Errorf("FromPins(5 X) = %v, want a RollError at roll 2", err)
+	}
+	if _, err := FromPins(layout("X 3")); !errors.As(err, &rollErr) || rollErr.Roll != 2 {
+		t.Errorf("FromPins(X 3) = %v, want a RollError at roll 2", err)
+	}
+}
+
+func TestReplaceFrame(t *testing.T) {
+	g, err := ParseNotation("34 X 5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.ReplaceFrame(0, []Ball{{Pins: 9}, {Pins: 1}}); err != nil {
+		t.Fatal(err)
+	}
+	if got := g.Notation(); got != "9/ X 5" {
+		t.Errorf("Notation() = %q, want %q", got, "9/ X 5")
+	}
+	if err := g.ReplaceFrame(1, []Ball{{Pins: 4}}); !errors.Is(err, ErrInvalidRoll) {
+		t.Errorf("an unfinished replacement = %v, want %v", err, ErrInvalidRoll)
+	}
+	if err := g.ReplaceFrame(5, nil); !errors.Is(err, ErrNoFrame) {
+		t.Errorf("ReplaceFrame(5) = %v, want %v", err, ErrNoFrame)
+	}
+}
@@ -0,0 +1,84 @@
+package scoring
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrInvalidHandicap = errors.New("scoring: invalid handicap")
+
+// Rounding says what happens to the fraction of a handicap.
+type Rounding string
+
+const (
+	RoundDown    Rounding = "down"
+	RoundUp      Rounding = "up"
+	RoundNearest Rounding = "nearest"
+)
+
+// Handicap is Percent percent of the difference between Basis and a
+// bowler's average, such as 90% of 220. A bowler has no handicap until Games
+// games establish an average, and never a negative one. The empty Rounding
+// rounds down.
+type Handicap struct {
+	Basis    int      `json:"basis"`
+	Percent  int      `json:"percent"`
+	Games    int      `json:"games"`
+	Rounding Rounding `json:"rounding,omitempty"`
+}
+
+// DefaultHandicap is the common 90% of 220 once three games are bowled.
+var DefaultHandicap = Handicap{Basis: 220, Percent: 90, Games: 3, Rounding: RoundDown}
+
+func (h Handicap) Validate() error {
+	switch {
+	case h.Basis <= 0:
+		return fmt.Errorf("%w: basis %d", ErrInvalidHandicap, h.Basis)
+	case h.Percent <= 0 || h.Percent > 100:
+		return fmt.Errorf("%w: %d percent", ErrInvalidHandicap, h.Percent)
+	case h.Games < 1:
+		return fmt.Errorf("%w: %d games", ErrInvalidHandicap, h.Games)
+	}
+	switch h.Rounding {
+	case "", RoundDown, RoundUp, RoundNearest:
+		return nil
+	}
+	return fmt.Errorf("%w: rounding %q", ErrInvalidHandicap, h.Rounding)
+}
+
+// Average is the average of totals with the fraction dropped, as league
+// averages are kept.
+func Average(totals []int) int {
+	if len(totals) == 0 {
+		return 0
+	}
+	sum := 0
+	for _, total := range totals {
+		sum += total
+	}
+	return sum / len(totals)
+}
+
+// Of returns the handicap of a bowler with the given average.
+func (h Handicap) Of(average int) int {
+	diff := (h.Basis - average) * h.Percent
+	if diff <= 0 {
+		return 0
+	}
+	switch h.Rounding {
+	case RoundUp:
+		return (diff + 99) / 100
+	case RoundNearest:
+		return (diff + 50) / 100
+	}
+	return diff / 100
+}
+
+// For returns the handicap earned by the totals of past games, and whether
+// they are enough games to establish an average.
+func (h Handicap) For(totals []int) (int, bool) {
+	if len(totals) < h.Games {
+		return 0, false
+	}
+	return h.Of(Average(totals)), true
+}
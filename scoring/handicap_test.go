@@ -0,0 +1,34 @@
+package scoring
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestHandicap(t *testing.T) {
+	for _, tt := range []struct {
+		handicap Handicap
+		average  int
+		want     int
+	}{
+		{DefaultHandicap, 150, 63},
+		{DefaultHandicap, 193, 24},
+		{Handicap{Basis: 220, Percent: 80, Games: 3, Rounding: RoundDown}, 193, 21},
+		{Handicap{Basis: 220, Percent: 80, Games: 3, Rounding: RoundUp}, 193, 22},
+		{Handicap{Basis: 220, Percent: 90, Games: 3, Rounding: RoundNearest}, 195, 23},
+		{DefaultHandicap, 230, 0},
+	} {
+		if got := tt.handicap.Of(tt.average); got != tt.want {
+			t.Errorf("%+v.Of(%d) = %d, want %d", tt.handicap, tt.average, got, tt.want)
+		}
+	}
+	if _, ok := DefaultHandicap.For([]int{200, 180}); ok {
+		t.Error("two games established an average of three")
+	}
+	if got, ok := DefaultHandicap.For([]int{200, 180, 161}); !ok || got != 36 {
+		t.Errorf("For() = %d, %v, want 36, true", got, ok)
+	}
+	if err := (Handicap{Basis: 220, Percent: 120, Games: 3}).Validate(); !errors.Is(err, ErrInvalidHandicap) {
+		t.Errorf("Validate() = %v, want %v", err, ErrInvalidHandicap)
+	}
+}
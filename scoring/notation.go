@@ -0,0 +1,79 @@
+package scoring
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrInvalidNotation = errors.New("scoring: invalid notation")
+
+// ParseNotation builds a game from standard notation, one whitespace-separated
+// token per frame such as "X 9/ 8- X X 7/ 9- X X X9/". A game in progress may
+// stop part way through a frame.
+func ParseNotation(s string, opts ...Option) (*Game, error) {
+	g := New(opts...)
+	if err := g.RollNotation(s); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// RollNotation rolls the balls written in standard notation, the first token
+// continuing the current frame. Marks are read as in the 21-slot layout and
+// may be lower case. A free frame must be written as a strike. The game is
+// left unchanged if the notation is invalid.
+func (g *Game) RollNotation(s string) error {
+	next := *g
+	next.balls = append([]Ball(nil), g.balls...)
+	tokens := strings.Fields(s)
+	start, _ := next.current()
+	for i, token := range tokens {
+		frame := start + i
+		if frame >= NumFrames {
+			return fmt.Errorf("%w: %q after the last frame", ErrInvalidNotation, token)
+		}
+		if next.variant.free(frame) {
+			if strings.ToUpper(token) != "X" {
+				return fmt.Errorf("%w: frame %d is free", ErrInvalidNotation, frame+1)
+			}
+			continue
+		}
+		for _, r := range strings.ToUpper(token) {
+			if f, _ := next.current(); f != frame {
+				return fmt.Errorf("%w: too many balls in frame %d", ErrInvalidNotation, frame+1)
+			}
+			if err := next.mark(string(r)); err != nil {
+				return fmt.Errorf("%w: %q in frame %d", err, string(r), frame+1)
+			}
+		}
+		if f, _ := next.current(); f == frame && i < len(tokens)-1 {
+			return fmt.Errorf("%w: frame %d is not finished", ErrInvalidNotation, frame+1)
+		}
+	}
+	*g = next
+	return nil
+}
+
+// Notation formats the game in standard notation, one token per frame.
+func (g *Game) Notation() string {
+	pins := g.Pins()
+	var tokens []string
+	for f := 0; f < NumFrames; f++ {
+		end := 2*f + 2
+		if f == NumFrames-1 {
+			end = NumSlots
+		}
+		token := ""
+		for _, mark := range pins[2*f : end] {
+			if mark != "yet" {
+				token += mark
+			}
+		}
+		if token == "" {
+			break
+		}
+		tokens = append(tokens, token)
+	}
+	return strings.Join(tokens, " ")
+}
@@ -0,0 +1,61 @@
+package scoring
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNotation(t *testing.T) {
+	for _, tt := range []struct {
+		notation string
+		want     string
+	}{
+		{"x 9/ 8- g- f7", "X 9/ 8- G- F7"},
+		{"  X\t9/  ", "X 9/"},
+		{"7", "7"},
+		{"", ""},
+	} {
+		g, err := ParseNotation(tt.notation)
+		if err != nil {
+			t.Fatalf("ParseNotation(%q) = %v", tt.notation, err)
+		}
+		if got := g.Notation(); got != tt.want {
+			t.Errorf("ParseNotation(%q).Notation() = %q, want %q", tt.notation, got, tt.want)
+		}
+	}
+
+	g, err := ParseNotation("X 7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.RollNotation("/ 9"); err != nil {
+		t.Fatal(err)
+	}
+	if got := g.Notation(); got != "X 7/ 9" {
+		t.Errorf("Notation() = %q, want %q", got, "X 7/ 9")
+	}
+	if err := g.RollNotation("- X 56"); !errors.Is(err, ErrInvalidRoll) {
+		t.Errorf("RollNotation() = %v, want %v", err, ErrInvalidRoll)
+	}
+	if got := g.Notation(); got != "X 7/ 9" {
+		t.Errorf("Notation() after an invalid roll = %q, want it unchanged", got)
+	}
+}
+
+func TestInvalidNotation(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		notation string
+	}{
+		{"unfinished frame", "5 X"},
+		{"eleventh frame", "X X X X X X X X X XXX X"},
+		{"two strikes in a frame", "XX"},
+		{"three balls in a frame", "5-3"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseNotation(tt.notation); !errors.Is(err, ErrInvalidNotation) {
+				t.Errorf("ParseNotation(%q) = %v, want %v", tt.notation, err, ErrInvalidNotation)
+			}
+		})
+	}
+}
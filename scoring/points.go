@@ -0,0 +1,94 @@
+package scoring
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrInvalidPoints = errors.New("scoring: invalid points")
+
+// Points is how a match between two teams is scored: Game points for each
+// game won on team total and Series points for the higher total over the
+// whole series, both with handicap. Tied games and series split their
+// points. An absent bowler bowls a blind of their average less Blind pins.
+type Points struct {
+	Game   float64 `json:"game"`
+	Series float64 `json:"series"`
+	Blind  int     `json:"blind"`
+}
+
+// DefaultPoints is a common league sheet: 2 points a game, 2 for the series
+// and blinds at average less 10.
+var DefaultPoints = Points{Game: 2, Series: 2, Blind: 10}
+
+func (p Points) Validate() error {
+	switch {
+	case p.Game < 0 || p.Series < 0 || p.Game+p.Series == 0:
+		return fmt.Errorf("%w: %g a game and %g a series", ErrInvalidPoints, p.Game, p.Series)
+	case p.Blind < 0:
+		return fmt.Errorf("%w: blind of %d pins", ErrInvalidPoints, p.Blind)
+	}
+	return nil
+}
+
+// Entry is the games one bowler bowled in a match and the handicap added to
+// each of them.
+type Entry struct {
+	Games    []int
+	Handicap int
+}
+
+// BlindEntry is what an absent bowler with the given average and handicap is
+// credited with for a series of games games.
+func (p Points) BlindEntry(average, handicap, games int) Entry {
+	score := average - p.Blind
+	if score < 0 {
+		score = 0
+	}
+	entry := Entry{Handicap: handicap}
+	for i := 0; i < games; i++ {
+		entry.Games = append(entry.Games, score)
+	}
+	return entry
+}
+
+// Total is the pins of a team in game i with handicap, or of the whole
+// series when i is negative.
+func Total(entries []Entry, i int) int {
+	total := 0
+	for _, e := range entries {
+		for j, score := range e.Games {
+			if i < 0 || i == j {
+				total += score + e.Handicap
+			}
+		}
+	}
+	return total
+}
+
+// Match returns the points two teams win against each other.
+func (p Points) Match(home, away []Entry) (float64, float64) {
+	games := 0
+	for _, e := range append(append([]Entry(nil), home...), away...) {
+		if len(e.Games) > games {
+			games = len(e.Games)
+		}
+	}
+	var homePoints, awayPoints float64
+	award := func(points float64, h, a int) {
+		switch {
+		case h > a:
+			homePoints += points
+		case h < a:
+			awayPoints += points
+		default:
+			homePoints += points / 2
+			awayPoints += points / 2
+		}
+	}
+	for i := 0; i < games; i++ {
+		award(p.Game, Total(home, i), Total(away, i))
+	}
+	award(p.Series, Total(home, -1), Total(away, -1))
+	return homePoints, awayPoints
+}
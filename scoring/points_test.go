@@ -0,0 +1,33 @@
+package scoring
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPoints(t *testing.T) {
+	home := []Entry{{Games: []int{200, 150, 180}, Handicap: 10}}
+	away := []Entry{{Games: []int{190, 160, 190}, Handicap: 10}}
+	for _, tt := range []struct {
+		name       string
+		home, away []Entry
+		want       [2]float64
+	}{
+		{"split games", home, away, [2]float64{2, 6}},
+		{"tie splits", home, home, [2]float64{4, 4}},
+		{"blind", home, []Entry{DefaultPoints.BlindEntry(180, 20, 3)}, [2]float64{3, 5}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			h, a := DefaultPoints.Match(tt.home, tt.away)
+			if h != tt.want[0] || a != tt.want[1] {
+				t.Errorf("Match() = %g, %g, want %g, %g", h, a, tt.want[0], tt.want[1])
+			}
+		})
+	}
+	if got := Total(home, -1); got != 560 {
+		t.Errorf("Total() = %d, want 560", got)
+	}
+	if err := (Points{Game: 0, Series: 0}).Validate(); !errors.Is(err, ErrInvalidPoints) {
+		t.Errorf("Validate() = %v, want %v", err, ErrInvalidPoints)
+	}
+}
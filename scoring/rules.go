@@ -0,0 +1,83 @@
+package scoring
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrUnknownRules = errors.New("scoring: unknown rule set")
+
+// Rules decides how frames are scored. FrameScore is given every ball of the
+// game and the index of the frame's first ball, and reports false while the
+// score still depends on balls not yet rolled. FillBalls reports whether a
+// strike or spare in the tenth frame earns extra balls.
+type Rules interface {
+	Name() string
+	FillBalls() bool
+	FrameScore(balls []Ball, start int, frame []Ball) (int, bool)
+}
+
+var (
+	// Traditional is the classic bonus scoring: a strike scores the next two
+	// balls on top of ten and a spare the next one.
+	Traditional Rules = traditional{}
+	// CurrentFrame is the World Bowling scoring: a strike scores 30, a spare
+	// 10 plus the pins of its first ball, and there are no fill balls.
+	CurrentFrame Rules = currentFrame{}
+)
+
+var ruleSets = []Rules{Traditional, CurrentFrame}
+
+// RuleSets returns every built-in rule set, the default first.
+func RuleSets() []Rules {
+	return append([]Rules(nil), ruleSets...)
+}
+
+// LookupRules finds a built-in rule set by name. The empty name, used by data
+// files written before rule sets existed, is Traditional.
+func LookupRules(name string) (Rules, error) {
+	if name == "" {
+		return Traditional, nil
+	}
+	for _, r := range ruleSets {
+		if r.Name() == name {
+			return r, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownRules, name)
+}
+
+type traditional struct{}
+
+func (traditional) Name() string    { return "traditional" }
+func (traditional) FillBalls() bool { return true }
+
+func (traditional) FrameScore(balls []Ball, start int, frame []Ball) (int, bool) {
+	count := len(frame)
+	if frame[0].Pins == NumPins || frame[0].Pins+frame[1].Pins == NumPins {
+		count = 3
+	}
+	if start+count > len(balls) {
+		return 0, false
+	}
+	score := 0
+	for _, b := range balls[start : start+count] {
+		score += b.Pins
+	}
+	return score, true
+}
+
+type currentFrame struct{}
+
+func (currentFrame) Name() string    { return "current-frame" }
+func (currentFrame) FillBalls() bool { return false }
+
+func (currentFrame) FrameScore(balls []Ball, start int, frame []Ball) (int, bool) {
+	switch {
+	case frame[0].Pins == NumPins:
+		return 30, true
+	case frame[0].Pins+frame[1].Pins == NumPins:
+		return NumPins + frame[0].Pins, true
+	}
+	return frame[0].Pins + frame[1].Pins, true
+}
@@ -0,0 +1,86 @@
+package scoring
+
+// pinSpots places each pin on the deck as {row, column}, with columns counted
+// in half-pin steps so that pins in neighbouring rows are one column apart.
+var pinSpots = [NumPins + 1][2]int{
+	{},
+	{0, 0},
+	{1, -1}, {1, 1},
+	{2, -2}, {2, 0}, {2, 2},
+	{3, -3}, {3, -1}, {3, 1}, {3, 3},
+}
+
+func touching(a, b int) bool {
+	dr := pinSpots[a][0] - pinSpots[b][0]
+	dc := pinSpots[a][1] - pinSpots[b][1]
+	if dr < 0 {
+		dr = -dr
+	}
+	if dc < 0 {
+		dc = -dc
+	}
+	switch dr {
+	case 0:
+		return dc == 2
+	case 1:
+		return dc == 1
+	case 2:
+		return dc == 0
+	}
+	return false
+}
+
+// Split reports whether the deck is a split: the headpin is down, two or more
+// pins are standing and a pin is down between or immediately ahead of them. A
+// pin directly behind another (such as the 2-8) is not a gap.
+func (d Deck) Split() bool {
+	pins := d.Pins()
+	if d.Has(1) || len(pins) < 2 {
+		return false
+	}
+	reached := Deck(0).Toggle(pins[0])
+	for grew := true; grew; {
+		grew = false
+		for _, pin := range pins {
+			if reached.Has(pin) {
+				continue
+			}
+			for _, other := range reached.Pins() {
+				if touching(pin, other) {
+					reached = reached.Toggle(pin)
+					grew = true
+					break
+				}
+			}
+		}
+	}
+	return reached != d
+}
+
+// Splits marks the slots, in the 21-slot layout, whose ball left a split on a
+// full rack. Only balls recorded pin by pin can leave a split.
+func (g *Game) Splits() [NumSlots]bool {
+	var splits [NumSlots]bool
+	for f, balls := range g.frames() {
+		for j, b := range balls {
+			splits[2*f+j] = b.Tracked && !b.Foul && fresh(f, balls[:j]) && b.Leave.Split()
+		}
+	}
+	return splits
+}
+
+// SplitStats counts the splits left in the game and how many of them were
+// converted to a spare.
+func (g *Game) SplitStats() (splits, converted int) {
+	pins := g.Pins()
+	for slot, split := range g.Splits() {
+		if !split {
+			continue
+		}
+		splits++
+		if slot+1 < NumSlots && pins[slot+1] == "/" {
+			converted++
+		}
+	}
+	return splits, converted
+}
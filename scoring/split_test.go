@@ -0,0 +1,47 @@
+package scoring
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	for _, tt := range []struct {
+		leave []int
+		split bool
+	}{
+		{[]int{7, 10}, true},
+		{[]int{4, 6}, true},
+		{[]int{5, 7}, true},
+		{[]int{3, 7}, true},
+		{[]int{2, 8}, false},
+		{[]int{2, 4, 5}, false},
+		{[]int{1, 7, 10}, false},
+		{[]int{10}, false},
+		{nil, false},
+	} {
+		d, err := DeckOf(tt.leave...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := d.Split(); got != tt.split {
+			t.Errorf("%v.Split() = %v, want %v", tt.leave, got, tt.split)
+		}
+	}
+	if _, err := DeckOf(11); !errors.Is(err, ErrInvalidLeave) {
+		t.Errorf("DeckOf(11) = %v, want %v", err, ErrInvalidLeave)
+	}
+
+	g := New()
+	split, _ := DeckOf(7, 10)
+	g.RollLeave(split)
+	g.RollLeave(0)
+	g.RollLeave(split)
+	g.Roll(1)
+	if splits, converted := g.SplitStats(); splits != 2 || converted != 1 {
+		t.Errorf("SplitStats() = %d, %d, want 2, 1", splits, converted)
+	}
+	if got := g.Leaves(); len(got[0]) != 2 || len(got[1]) != 0 || len(got[2]) != 2 {
+		t.Errorf("Leaves() = %v", got)
+	}
+}
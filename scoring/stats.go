@@ -0,0 +1,157 @@
+package scoring
+
+// Stats summarizes a series of games. Doubles count runs of exactly two
+// strikes and Turkeys runs of three or more, so a strike run is never counted
+// twice. Free strikes granted by a variant are left out of the ball counts.
+type Stats struct {
+	Games int
+
+	Strikes             int
+	StrikeChances       int
+	Spares              int
+	SpareChances        int
+	OpenFrames          int
+	Frames              int
+	FirstBallPins       int
+	FirstBalls          int
+	Doubles             int
+	Turkeys             int
+	CleanGames          int
+	TenthFramePins      int
+	TenthFrameStrikes   int
+	TenthFrameChances   int
+	TenthFrameCompleted int
+}
+
+func ratio(n, d int) float64 {
+	if d == 0 {
+		return 0
+	}
+	return float64(n) / float64(d)
+}
+
+func (s Stats) StrikeRate() float64      { return ratio(s.Strikes, s.StrikeChances) }
+func (s Stats) SpareRate() float64       { return ratio(s.Spares, s.SpareChances) }
+func (s Stats) OpenRate() float64        { return ratio(s.OpenFrames, s.Frames) }
+func (s Stats) FirstBallAvg() float64    { return ratio(s.FirstBallPins, s.FirstBalls) }
+func (s Stats) TenthFrameAvg() float64   { return ratio(s.TenthFramePins, s.TenthFrameCompleted) }
+func (s Stats) TenthStrikeRate() float64 { return ratio(s.TenthFrameStrikes, s.TenthFrameChances) }
+
+// Summarize collects the statistics of games.
+func Summarize(games []*Game) Stats {
+	var s Stats
+	for _, g := range games {
+		s.add(g)
+	}
+	return s
+}
+
+func (s *Stats) add(g *Game) {
+	s.Games++
+	run := 0
+	endRun := func() {
+		switch {
+		case run == 2:
+			s.Doubles++
+		case run >= 3:
+			s.Turkeys++
+		}
+		run = 0
+	}
+	clean := true
+	frames := g.Frames()
+	for f, balls := range g.frames() {
+		if !balls[0].Free {
+			s.FirstBallPins += balls[0].Pins
+			s.FirstBalls++
+		}
+		for j, b := range balls {
+			if !fresh(f, balls[:j]) || b.Free {
+				continue
+			}
+			s.StrikeChances++
+			if f == NumFrames-1 {
+				s.TenthFrameChances++
+			}
+			if b.Pins == NumPins {
+				s.Strikes++
+				if f == NumFrames-1 {
+					s.TenthFrameStrikes++
+				}
+				run++
+				continue
+			}
+			endRun()
+			if j+1 < len(balls) {
+				s.SpareChances++
+				if b.Pins+balls[j+1].Pins == NumPins {
+					s.Spares++
+				}
+			}
+		}
+		if !g.frameComplete(f, balls) {
+			continue
+		}
+		s.Frames++
+		if balls[0].Pins != NumPins && balls[0].Pins+balls[1].Pins < NumPins {
+			s.OpenFrames++
+			clean = false
+		}
+		if f == NumFrames-1 && frames[f].Scored {
+			prev := 0
+			if f > 0 {
+				prev = frames[f-1].Score
+			}
+			s.TenthFramePins += frames[f].Score - prev
+			s.TenthFrameCompleted++
+		}
+	}
+	endRun()
+	if clean && g.Complete() {
+		s.CleanGames++
+	}
+}
+
+// SummarizeBox collects the statistics of three-ball games. Only the strike,
+// spare, open frame and first ball counts apply to them.
+func SummarizeBox(games []*BoxGame) Stats {
+	var s Stats
+	for _, g := range games {
+		s.Games++
+		rack := g.discipline.Rack()
+		for f, balls := range g.frames() {
+			s.FirstBallPins += balls[0]
+			s.FirstBalls++
+			standing := rack
+			onRack := 0
+			for _, b := range balls {
+				switch onRack {
+				case 0:
+					s.StrikeChances++
+					if b == rack {
+						s.Strikes++
+					}
+				case 1:
+					s.SpareChances++
+					if b == standing {
+						s.Spares++
+					}
+				}
+				standing -= b
+				onRack++
+				if standing == 0 {
+					standing = rack
+					onRack = 0
+				}
+			}
+			if !g.frameComplete(f, balls) {
+				continue
+			}
+			s.Frames++
+			if balls[0] != rack && balls[0]+balls[1] != rack {
+				s.OpenFrames++
+			}
+		}
+	}
+	return s
+}
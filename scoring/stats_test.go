@@ -0,0 +1,20 @@
+package scoring
+
+import (
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	perfect, _ := ParseNotation("X X X X X X X X X XXX")
+	open, _ := ParseNotation("9- X X 8/ 7- X -- X X 9/X")
+	s := Summarize([]*Game{perfect, open})
+	if s.Games != 2 || s.Strikes != 12+6 || s.CleanGames != 1 {
+		t.Errorf("Summarize() = %+v", s)
+	}
+	if s.Turkeys != 1 || s.Doubles != 2 {
+		t.Errorf("Doubles, Turkeys = %d, %d, want 2, 1", s.Doubles, s.Turkeys)
+	}
+	if s.OpenFrames != 3 {
+		t.Errorf("OpenFrames = %d, want 3", s.OpenFrames)
+	}
+}
@@ -0,0 +1,85 @@
+package scoring
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrUnknownVariant = errors.New("scoring: unknown game variant")
+
+// Variant is a handicap-style game played on top of a rule set. NoTap is the
+// first-ball count on a full rack that already scores as a strike (0 for
+// none), and FreeFrames lists the frames, counted from 1, that are scored as
+// strikes without being bowled.
+type Variant struct {
+	Name       string
+	NoTap      int
+	FreeFrames []int
+}
+
+var (
+	Standard     = Variant{Name: "standard"}
+	NineNoTap    = Variant{Name: "9-pin-no-tap", NoTap: 9}
+	ThreeSixNine = Variant{Name: "3-6-9", FreeFrames: []int{3, 6, 9}}
+)
+
+var variants = []Variant{Standard, NineNoTap, ThreeSixNine}
+
+// Variants returns every built-in variant, the default first.
+func Variants() []Variant {
+	return append([]Variant(nil), variants...)
+}
+
+// LookupVariant finds a built-in variant by name. The empty name is Standard.
+func LookupVariant(name string) (Variant, error) {
+	if name == "" {
+		return Standard, nil
+	}
+	for _, v := range variants {
+		if v.Name == name {
+			return v, nil
+		}
+	}
+	return Variant{}, fmt.Errorf("%w: %q", ErrUnknownVariant, name)
+}
+
+func (v Variant) free(f int) bool {
+	for _, frame := range v.FreeFrames {
+		if frame == f+1 {
+			return true
+		}
+	}
+	return false
+}
+
+func WithVariant(v Variant) Option {
+	return func(g *Game) {
+		g.variant = v
+	}
+}
+
+func (g *Game) Variant() Variant {
+	return g.variant
+}
+
+// fill rolls the free strikes the variant grants at the start of a frame.
+func (g *Game) fill() {
+	for !g.Complete() {
+		f, balls := g.current()
+		if len(balls) > 0 || !g.variant.free(f) {
+			return
+		}
+		g.balls = append(g.balls, Ball{Pins: NumPins, Free: true})
+	}
+}
+
+// FreeSlots marks the slots, in the 21-slot layout, holding a free strike.
+func (g *Game) FreeSlots() [NumSlots]bool {
+	var free [NumSlots]bool
+	for f, balls := range g.frames() {
+		for j, b := range balls {
+			free[2*f+j] = b.Free
+		}
+	}
+	return free
+}
@@ -0,0 +1,61 @@
+package scoring
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNineNoTap(t *testing.T) {
+	g := New(WithVariant(NineNoTap))
+	for !g.Complete() {
+		if err := g.Roll(9); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := g.Total(); got != 300 {
+		t.Errorf("Total() = %d, want 300", got)
+	}
+	if pins := g.Pins(); pins[0] != "N" || pins[20] != "N" {
+		t.Errorf("Pins() = %v, want no-tap marks", pins)
+	}
+	g = New(WithVariant(NineNoTap))
+	g.Roll(8)
+	g.Roll(1)
+	if pins := g.Pins(); pins[0] != "8" || pins[1] != "1" {
+		t.Errorf("Pins() = %v, want 8 1 on a second ball", pins)
+	}
+}
+
+func TestThreeSixNine(t *testing.T) {
+	g, err := ParseNotation("9- 9/", WithVariant(ThreeSixNine))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := g.Times(); got != 6 {
+		t.Fatalf("Times() = %d, want 6 after the free third frame", got)
+	}
+	if free := g.FreeSlots(); !free[4] || free[6] {
+		t.Errorf("FreeSlots() = %v, want only the third frame free", free)
+	}
+	if err := g.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if got := g.Times(); got != 3 {
+		t.Errorf("Times() = %d after undo, want 3", got)
+	}
+	if free := g.FreeSlots(); free[4] {
+		t.Error("the free strike is still there after undo")
+	}
+	if err := g.RollNotation("/ X 9- 9- X 9- 9- X 9-"); err != nil {
+		t.Fatal(err)
+	}
+	if !g.Complete() {
+		t.Fatal("game is not complete")
+	}
+	if got := g.Total(); got != 131 {
+		t.Errorf("Total() = %d, want 131", got)
+	}
+	if _, err := ParseNotation("9- 9- 9-", WithVariant(ThreeSixNine)); !errors.Is(err, ErrInvalidNotation) {
+		t.Errorf("a bowled free frame = %v, want %v", err, ErrInvalidNotation)
+	}
+}
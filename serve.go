@@ -0,0 +1,226 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ryokpen87044/bowlingScorer/scoring"
+)
+
+type server struct {
+	m  Model
+	mu sync.Mutex
+}
+
+// rollRequest is the body of a posted roll: either a mark as typed in the
+// score input or, for ten-pin, the pins left standing after the ball.
+type rollRequest struct {
+	Roll  string `json:"roll"`
+	Leave []int  `json:"leave"`
+}
+
+// tenPinRoll is the roll the request adds to a ten-pin game.
+func (req rollRequest) tenPinRoll() (func(*scoring.Game) error, error) {
+	if req.Leave == nil {
+		return func(game *scoring.Game) error {
+			return rollInput(game, req.Roll)
+		}, nil
+	}
+	deck, err := scoring.DeckOf(req.Leave...)
+	if err != nil {
+		return nil, err
+	}
+	return func(game *scoring.Game) error {
+		return game.RollLeave(deck)
+	}, nil
+}
+
+// fits tries the roll on a copy of the game it goes into, a new one when the
+// game in progress is over, so that a finished game is only archived for a
+// roll that counts.
+func (req rollRequest) fits(bowl Bowl, roll func(*scoring.Game) error) error {
+	if bowl.over() {
+		bowl = bowl.resetGame()
+	}
+	if !bowl.tenPin() {
+		game, err := bowl.box()
+		if err != nil {
+			return err
+		}
+		return boxRollInput(game, req.Roll)
+	}
+	game, err := bowl.game()
+	if err != nil {
+		return err
+	}
+	return roll(game)
+}
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(v)
+}
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// loadStatus is the status of a player that could not be loaded. Only a
+// missing player is not found; anything else is left on disk untouched, and
+// data that needs the player to deal with it first is a conflict.
+func loadStatus(err error) int {
+	switch {
+	case errors.Is(err, errNoPlayer):
+		return http.StatusNotFound
+	case errors.Is(err, errInvalidName):
+		return http.StatusBadRequest
+	case errors.Is(err, errNewerSchema), errors.Is(err, errUnresolved):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+func (s *server) players(w http.ResponseWriter) {
+	names, err := s.m.store.Players()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, names)
+}
+func (s *server) roll(w http.ResponseWriter, r *http.Request, name string) {
+	var req rollRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	m := s.m
+	var err error
+	if m.Bowl, err = m.loadOrCreate(name); err != nil {
+		writeError(w, loadStatus(err), err)
+		return
+	}
+	roll, err := req.tenPinRoll()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := req.fits(m.Bowl, roll); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	if m.Bowl.over() {
+		m.Bowl, m.scoreSel = m.nextGame()
+	}
+	times := m.Bowl.Times
+	if m.Bowl.tenPin() {
+		m.Bowl = m.addScore(roll)
+	} else {
+		m.Bowl = m.addBoxScore(req.Roll)
+	}
+	if times == m.Bowl.Times {
+		writeError(w, http.StatusUnprocessableEntity, scoring.ErrInvalidRoll)
+		return
+	}
+	m.logger.Info(fmt.Sprintf("Update Score of %s over HTTP.", m.Bowl.Name))
+	if err := m.write(); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, m.Bowl)
+}
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/events" {
+		s.m.events.ServeHTTP(w, r)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.m.logger.Info(fmt.Sprintf("%s %s", r.Method, r.URL.Path))
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if parts[0] != "players" {
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+		return
+	}
+	if len(parts) == 1 {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+			return
+		}
+		s.players(w)
+		return
+	}
+	name := parts[1]
+	if !validName(name) {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("%w: %q", errInvalidName, name))
+		return
+	}
+	if len(parts) == 3 && parts[2] == "rolls" {
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+			return
+		}
+		s.roll(w, r, name)
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+	bowl, err := s.m.load(name)
+	if err != nil {
+		writeError(w, loadStatus(err), err)
+		return
+	}
+	switch {
+	case len(parts) == 2:
+		writeJSON(w, http.StatusOK, bowl)
+	case len(parts) == 3 && parts[2] == "archives":
+		archives := bowl.Archives
+		if archives == nil {
+			archives = []Archive{}
+		}
+		writeJSON(w, http.StatusOK, archives)
+	case len(parts) == 4 && parts[2] == "archives":
+		n, err := strconv.Atoi(parts[3])
+		if err != nil || n < 1 || n > len(bowl.Archives) {
+			writeError(w, http.StatusNotFound, fmt.Errorf("no game %q", parts[3]))
+			return
+		}
+		writeJSON(w, http.StatusOK, bowl.Archives[n-1])
+	default:
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+	}
+}
+
+// serveCommand exposes the data directory over HTTP:
+//
+//	GET  /players
+//	GET  /players/{name}
+//	POST /players/{name}/rolls
+//	GET  /players/{name}/archives
+//	GET  /players/{name}/archives/{n}
+//	GET  /events
+func (m Model) serveCommand(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := flags.String("addr", ":8080", "listen on `address`")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return errUsage
+	}
+	if m.events == nil {
+		m.events = newBroker()
+	}
+	m.logger.Info(fmt.Sprintf("Serve on \"%s\".", *addr))
+	fmt.Fprintf(stdout, "Serving data on %s\n", *addr)
+	return http.ListenAndServe(*addr, &server{m: m})
+}
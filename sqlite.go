@@ -0,0 +1,187 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	_ "modernc.org/sqlite"
+)
+
+// sqliteStore keeps players in an embedded SQLite database. Each archive is a
+// row of its own, with its time, discipline and total in columns so that a
+// long history can be queried without decoding every game.
+type sqliteStore struct {
+	db *sql.DB
+}
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS players (
+	name TEXT PRIMARY KEY,
+	bowl TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS archives (
+	player     TEXT NOT NULL REFERENCES players (name) ON DELETE CASCADE,
+	seq        INTEGER NOT NULL,
+	time       TEXT NOT NULL,
+	discipline TEXT NOT NULL,
+	total      INTEGER NOT NULL,
+	archive    TEXT NOT NULL,
+	PRIMARY KEY (player, seq)
+);
+CREATE INDEX IF NOT EXISTS archives_total ON archives (player, total);
+`
+
+func openSQLite(path string) (sqliteStore, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return sqliteStore{}, err
+	}
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return sqliteStore{}, err
+	}
+	return sqliteStore{db: db}, nil
+}
+func (s sqliteStore) Load(name string) (Bowl, error) {
+	var bowl Bowl
+	var data string
+	err := s.db.QueryRow(`SELECT bowl FROM players WHERE name = ?`, name).Scan(&data)
+	if err == sql.ErrNoRows {
+		return bowl, fmt.Errorf("%w: %q", errNoPlayer, name)
+	}
+	if err != nil {
+		return bowl, err
+	}
+	if err := json.Unmarshal([]byte(data), &bowl); err != nil {
+		return bowl, fmt.Errorf("decode player %q: %w", name, err)
+	}
+	rows, err := s.db.Query(`SELECT archive FROM archives WHERE player = ? ORDER BY seq`, name)
+	if err != nil {
+		return bowl, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var a Archive
+		if err := rows.Scan(&data); err != nil {
+			return bowl, err
+		}
+		if err := json.Unmarshal([]byte(data), &a); err != nil {
+			return bowl, fmt.Errorf("decode a game of %q: %w", name, err)
+		}
+		bowl.Archives = append(bowl.Archives, a)
+	}
+	return bowl, rows.Err()
+}
+func insertArchive(tx *sql.Tx, name string, seq int, a Archive) error {
+	data, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+	return writeArchive(tx, name, seq, a, data)
+}
+func writeArchive(tx *sql.Tx, name string, seq int, a Archive, data []byte) error {
+	_, err := tx.Exec(`INSERT INTO archives (player, seq, time, discipline, total, archive) VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (player, seq) DO UPDATE SET time = excluded.time, discipline = excluded.discipline, total = excluded.total, archive = excluded.archive`,
+		name, seq, a.Time, disciplineName(a.Discipline), a.Scores[10], string(data))
+	return err
+}
+
+// Save upserts the player and writes only the archives that are not stored
+// exactly as they are: the games finished since the last save, and any game
+// that was repaired or moved up in place of one that was removed. Games past
+// the end of the player's archives are deleted.
+func (s sqliteStore) Save(bowl Bowl) error {
+	bowl.SchemaVersion = schemaVersion
+	archives := bowl.Archives
+	bowl.Archives = nil
+	data, err := json.Marshal(bowl)
+	if err != nil {
+		return err
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(`INSERT INTO players (name, bowl) VALUES (?, ?) ON CONFLICT (name) DO UPDATE SET bowl = excluded.bowl`, bowl.Name, string(data)); err != nil {
+		return err
+	}
+	stored, err := storedGames(tx, bowl.Name)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM archives WHERE player = ? AND seq > ?`, bowl.Name, len(archives)); err != nil {
+		return err
+	}
+	for i, a := range archives {
+		data, err := json.Marshal(a)
+		if err != nil {
+			return err
+		}
+		if stored[i+1] == string(data) {
+			continue
+		}
+		if err := writeArchive(tx, bowl.Name, i+1, a, data); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// storedGames returns the archives of a player as stored, by seq.
+func storedGames(tx *sql.Tx, name string) (map[int]string, error) {
+	rows, err := tx.Query(`SELECT seq, archive FROM archives WHERE player = ?`, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	stored := map[int]string{}
+	for rows.Next() {
+		var seq int
+		var data string
+		if err := rows.Scan(&seq, &data); err != nil {
+			return nil, err
+		}
+		stored[seq] = data
+	}
+	return stored, rows.Err()
+}
+func (s sqliteStore) AppendArchive(name string, archive Archive) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	var exists int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM players WHERE name = ?`, name).Scan(&exists); err != nil {
+		return err
+	}
+	if exists == 0 {
+		return fmt.Errorf("%w: %q", errNoPlayer, name)
+	}
+	var seq int
+	if err := tx.QueryRow(`SELECT COALESCE(MAX(seq), 0) FROM archives WHERE player = ?`, name).Scan(&seq); err != nil {
+		return err
+	}
+	if err := insertArchive(tx, name, seq+1, archive); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+func (s sqliteStore) Players() ([]string, error) {
+	rows, err := s.db.Query(`SELECT name FROM players ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	names := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
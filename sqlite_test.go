@@ -0,0 +1,115 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ryokpen87044/bowlingScorer/scoring"
+)
+
+func testArchive(t *testing.T, notation, date string) Archive {
+	t.Helper()
+	game, err := scoring.ParseNotation(notation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := archiveGame(game, date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func openTestSQLite(t *testing.T) sqliteStore {
+	t.Helper()
+	store, err := openSQLite(filepath.Join(t.TempDir(), "bowling.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.db.Close() })
+	return store
+}
+
+func TestSQLiteSave(t *testing.T) {
+	store := openTestSQLite(t)
+	bowl := initBowl()
+	bowl.Name = "alice"
+	bowl.Archives = []Archive{
+		testArchive(t, "X X X X X X X X X XXX", "2024/01/01"),
+		testArchive(t, "9- 9- 9- 9- 9- 9- 9- 9- 9- 9-", "2024/01/02"),
+		testArchive(t, "5/ 5/ 5/ 5/ 5/ 5/ 5/ 5/ 5/ 5/5", "2024/01/03"),
+	}
+	if err := store.Save(bowl); err != nil {
+		t.Fatal(err)
+	}
+
+	// A repair that leaves the total alone must still be saved.
+	bowl.Archives[1].Scores[3] = 999
+	if err := store.Save(bowl); err != nil {
+		t.Fatal(err)
+	}
+	got, err := store.Load("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Archives[1].Scores[3] != 999 {
+		t.Errorf("changed frame score = %d, want 999", got.Archives[1].Scores[3])
+	}
+	bowl.Archives[1].Scores[3] = 27
+	if err := store.Save(bowl); err != nil {
+		t.Fatal(err)
+	}
+
+	// Dropping a game moves the later ones up.
+	bowl.Archives = append(bowl.Archives[:0:0], bowl.Archives[0], bowl.Archives[2])
+	if err := store.Save(bowl); err != nil {
+		t.Fatal(err)
+	}
+	if got, err = store.Load("alice"); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Archives) != 2 || got.Archives[1].Time != "2024/01/03" || got.Archives[1].Scores[10] != 150 {
+		t.Errorf("archives after a removal = %+v", got.Archives)
+	}
+
+	if err := store.AppendArchive("alice", testArchive(t, "9- 9- 9- 9- 9- 9- 9- 9- 9- 9-", "2024/01/04")); err != nil {
+		t.Fatal(err)
+	}
+	if got, err = store.Load("alice"); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Archives) != 3 || got.Archives[2].Scores[10] != 90 {
+		t.Errorf("archives after an append = %+v", got.Archives)
+	}
+	if got.SchemaVersion != schemaVersion {
+		t.Errorf("SchemaVersion = %d, want %d", got.SchemaVersion, schemaVersion)
+	}
+}
+
+func TestSQLiteSaveUnchanged(t *testing.T) {
+	store := openTestSQLite(t)
+	store.db.SetMaxOpenConns(1)
+	bowl := initBowl()
+	bowl.Name = "bob"
+	bowl.Archives = []Archive{testArchive(t, "X X X X X X X X X XXX", "2024/01/01")}
+	if err := store.Save(bowl); err != nil {
+		t.Fatal(err)
+	}
+	changes := func() int {
+		var n int
+		if err := store.db.QueryRow(`SELECT total_changes()`).Scan(&n); err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+	before := changes()
+	if err := store.Save(bowl); err != nil {
+		t.Fatal(err)
+	}
+	if got := changes() - before; got != 1 {
+		t.Errorf("saving again changed %d rows, want only the player", got)
+	}
+	if _, err := store.Load("nobody"); err == nil {
+		t.Error("Load(nobody) = nil error")
+	}
+}
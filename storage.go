@@ -0,0 +1,142 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var errNoPlayer = errors.New("no such player")
+var errInvalidName = errors.New("invalid name")
+
+// reservedChars cannot be part of a name, which is also a file name.
+var reservedChars = regexp.MustCompile(`[\\/:*?"<>|]`)
+
+// validName reports whether name can name a player or league: it is not
+// blank, not hidden, not padded with spaces and holds no path or reserved
+// characters.
+func validName(name string) bool {
+	return name != "" && name == strings.TrimSpace(name) && !strings.HasPrefix(name, ".") && !reservedChars.MatchString(name)
+}
+
+// cleanName makes a valid name of typed input by trimming it and replacing
+// every reserved character and a leading "." with "-". Blank input stays
+// empty.
+func cleanName(name string) string {
+	name = reservedChars.ReplaceAllString(strings.TrimSpace(name), "-")
+	if strings.HasPrefix(name, ".") {
+		name = "-" + name[1:]
+	}
+	return name
+}
+
+// Storage keeps every player's Bowl. AppendArchive adds a finished game
+// without rewriting the games before it where the backend allows.
+type Storage interface {
+	Load(name string) (Bowl, error)
+	Save(bowl Bowl) error
+	AppendArchive(name string, archive Archive) error
+	Players() ([]string, error)
+}
+
+// jsonStore keeps one JSON file per player in dir.
+type jsonStore struct {
+	dir string
+}
+
+func (s jsonStore) path(name string) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%s.json", name))
+}
+
+// Load upgrades old data to the current schema in memory. The file itself
+// is only upgraded when the player is saved or migrated.
+func (s jsonStore) Load(name string) (Bowl, error) {
+	var bowl Bowl
+	raw, err := s.raw(name)
+	if err != nil {
+		return bowl, err
+	}
+	upgraded, _, err := migrate(raw)
+	if errors.Is(err, errNewerSchema) {
+		return bowl, err
+	}
+	if err == nil {
+		err = json.Unmarshal(upgraded, &bowl)
+	}
+	if err != nil {
+		return bowl, fmt.Errorf("decode %s: %w", s.path(name), err)
+	}
+	return bowl, nil
+}
+func (s jsonStore) raw(name string) ([]byte, error) {
+	raw, err := os.ReadFile(s.path(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %q", errNoPlayer, name)
+	}
+	return raw, err
+}
+
+// Save writes the player to a temporary file in the same directory and then
+// renames it into place, so a crash never leaves a half-written file behind.
+// A file of an older version is backed up first.
+func (s jsonStore) Save(bowl Bowl) error {
+	if err := s.backup(bowl.Name); err != nil {
+		return err
+	}
+	bowl.SchemaVersion = schemaVersion
+	return saveJSON(s.dir, bowl.Name, bowl)
+}
+
+// saveJSON writes v as indented JSON to dir/<name>.json through a temporary
+// file that is renamed into place.
+func saveJSON(dir, name string, v any) error {
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(dir, fmt.Sprintf(".%s.*.tmp", name))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Chmod(0644); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), filepath.Join(dir, fmt.Sprintf("%s.json", name)))
+}
+func (s jsonStore) AppendArchive(name string, archive Archive) error {
+	bowl, err := s.Load(name)
+	if err != nil {
+		return err
+	}
+	bowl.Archives = append(bowl.Archives, archive)
+	return s.Save(bowl)
+}
+func (s jsonStore) Players() ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, path := range paths {
+		names = append(names, strings.TrimSuffix(filepath.Base(path), ".json"))
+	}
+	return names, nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNames(t *testing.T) {
+	for _, tt := range []struct {
+		input, clean string
+		valid        bool
+	}{
+		{"alice", "alice", true},
+		{"Mary Ann", "Mary Ann", true},
+		{"", "", false},
+		{"  ", "", false},
+		{".hidden", "-hidden", false},
+		{"..", "-.", false},
+		{"a/b", "a-b", false},
+		{`c:\d`, "c--d", false},
+		{" bob? ", "bob-", false},
+	} {
+		if got := validName(tt.input); got != tt.valid {
+			t.Errorf("validName(%q) = %v, want %v", tt.input, got, tt.valid)
+		}
+		clean := cleanName(tt.input)
+		if clean != tt.clean {
+			t.Errorf("cleanName(%q) = %q, want %q", tt.input, clean, tt.clean)
+		}
+		if clean != "" && !validName(clean) {
+			t.Errorf("cleanName(%q) = %q is not valid", tt.input, clean)
+		}
+	}
+}
+
+func TestInvalidNames(t *testing.T) {
+	m := testModel(t)
+	for _, name := range []string{"../alice", ".alice", ""} {
+		if _, err := m.load(name); !errors.Is(err, errInvalidName) {
+			t.Errorf("load(%q) = %v, want %v", name, err, errInvalidName)
+		}
+		if _, err := m.loadOrCreate(name); !errors.Is(err, errInvalidName) {
+			t.Errorf("loadOrCreate(%q) = %v, want %v", name, err, errInvalidName)
+		}
+	}
+	server := &server{m: m}
+	for _, path := range []string{"/players/.alice", "/players/.alice/rolls"} {
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"roll":"5"}`)))
+		if w.Code != http.StatusBadRequest && w.Code != http.StatusMethodNotAllowed {
+			t.Errorf("POST %s = %d", path, w.Code)
+		}
+	}
+	var out strings.Builder
+	if err := m.leagueCommand([]string{"new", ".league"}, &out); err == nil {
+		t.Error("a hidden league name was accepted")
+	}
+	lane, _, err := m.loadLane(" a/b, ,.c ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lane) != 2 || lane[0].Name != "a-b" || lane[1].Name != "-c" {
+		t.Errorf("loadLane() = %v", lane)
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// scoreEvent is pushed to every listener after a game changes. Pins or Marks
+// hold the whole game in progress, whichever the discipline uses.
+type scoreEvent struct {
+	Player   string   `json:"player"`
+	Kind     string   `json:"kind"`
+	Frame    int      `json:"frame"`
+	Rolls    []string `json:"rolls,omitempty"`
+	Pins     []string `json:"pins,omitempty"`
+	Marks    []string `json:"marks,omitempty"`
+	Scores   [11]int  `json:"scores"`
+	MaxScore int      `json:"maxScore"`
+	Over     bool     `json:"over"`
+}
+
+// broker fans score events out to server-sent event streams. Listeners that
+// fall behind miss events rather than holding up scoring.
+type broker struct {
+	mu        sync.Mutex
+	listeners map[chan scoreEvent]bool
+}
+
+func newBroker() *broker {
+	return &broker{listeners: map[chan scoreEvent]bool{}}
+}
+func (b *broker) subscribe() chan scoreEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	ch := make(chan scoreEvent, 16)
+	b.listeners[ch] = true
+	return ch
+}
+func (b *broker) unsubscribe(ch chan scoreEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.listeners, ch)
+}
+func (b *broker) publish(e scoreEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.listeners {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+// ServeHTTP streams score events, only those of one player with ?player=.
+func (b *broker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	flusher.Flush()
+	player := r.URL.Query().Get("player")
+	ch := b.subscribe()
+	defer b.unsubscribe(ch)
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e := <-ch:
+			if player != "" && e.Player != player {
+				continue
+			}
+			data, err := json.Marshal(e)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "event: score\ndata: %s\n\n", data)
+			flusher.Flush()
+		}
+	}
+}
+
+// publish sends what turned before into m.Bowl: kind is "roll", "undo",
+// "edit" or "next". Rolls holds every mark a roll added, so notation that
+// rolls several balls at once is sent whole, the marks an undo took away, or
+// the marks of the frame an edit re-entered.
+func (m Model) publish(kind string, before Bowl) {
+	if m.events == nil {
+		return
+	}
+	e := scoreEvent{
+		Player:   m.Bowl.Name,
+		Kind:     kind,
+		Frame:    before.frame() + 1,
+		Rolls:    rolled(before, m.Bowl),
+		Scores:   m.Bowl.Scores,
+		MaxScore: m.Bowl.MaxScore,
+		Over:     m.Bowl.over(),
+	}
+	switch kind {
+	case "undo":
+		e.Frame = m.Bowl.frame() + 1
+	case "edit":
+		e.Frame = m.editFrame + 1
+		e.Rolls = m.Bowl.frameMarks(m.editFrame)
+	case "next":
+		e.Frame = 1
+		e.Rolls = nil
+	}
+	if m.Bowl.tenPin() {
+		e.Pins = m.Bowl.Pins[:]
+	} else {
+		e.Marks = m.Bowl.Marks
+	}
+	m.events.publish(e)
+}
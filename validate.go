@@ -0,0 +1,278 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/ryokpen87044/bowlingScorer/scoring"
+)
+
+// currentGame is the Game of a finding about the game in progress.
+const currentGame = -1
+
+// errUnresolved refuses to change a player whose game in progress cannot be
+// kept as saved and has not been repaired or discarded yet.
+var errUnresolved = errors.New("the game in progress has problems; open the player to repair or discard it first")
+
+// finding is one problem in a player's data. Game is the index of the archive
+// it was found in, or currentGame, and Roll the roll it is about, counting from
+// 1, or 0 when it is not about a single roll.
+type finding struct {
+	Game    int
+	Roll    int
+	Problem string
+}
+
+func (f finding) String() string {
+	where := "current game"
+	if f.Game != currentGame {
+		where = fmt.Sprintf("game %d", f.Game+1)
+	}
+	if f.Roll > 0 {
+		where = fmt.Sprintf("%s, roll %d", where, f.Roll)
+	}
+	return fmt.Sprintf("%s: %s", where, f.Problem)
+}
+
+// fatal reports whether the data cannot be kept as it is. Rolls are only
+// added to the game in progress; archives are just drawn and counted.
+func (f finding) fatal() bool {
+	return f.Game == currentGame
+}
+func anyFatal(findings []finding) bool {
+	for _, f := range findings {
+		if f.fatal() {
+			return true
+		}
+	}
+	return false
+}
+func recordFinding(game int, err error) finding {
+	var rollErr *scoring.RollError
+	if !errors.As(err, &rollErr) {
+		return finding{Game: game, Problem: err.Error()}
+	}
+	problem := fmt.Sprintf("%q is not a possible roll here", rollErr.Mark)
+	if errors.Is(err, scoring.ErrInvalidLeave) {
+		problem = fmt.Sprintf("%q does not match the pins left standing", rollErr.Mark)
+	}
+	return finding{Game: game, Roll: rollErr.Roll, Problem: problem}
+}
+
+// scoresFinding compares saved scores with those the rolls give and reports
+// the first frame where they differ.
+func scoresFinding(game int, saved, rescored [11]int) []finding {
+	for i := range saved {
+		if saved[i] == rescored[i] {
+			continue
+		}
+		problem := fmt.Sprintf("frame %d is saved as %d but the rolls give %d", i, saved[i], rescored[i])
+		switch {
+		case saved[i] == -1:
+			problem = fmt.Sprintf("frame %d is saved unscored but the rolls give %d", i, rescored[i])
+		case rescored[i] == -1:
+			problem = fmt.Sprintf("frame %d is saved as %d but the rolls leave it unscored", i, saved[i])
+		}
+		return []finding{{Game: game, Problem: problem}}
+	}
+	return nil
+}
+
+// rescore replays the rolls of the game in progress and takes the scores,
+// maximum and number of rolls from them.
+func (b Bowl) rescore() (Bowl, error) {
+	m := Model{Bowl: b}
+	if b.tenPin() {
+		game, err := b.game()
+		if err != nil {
+			return b, err
+		}
+		return m.applyGame(game), nil
+	}
+	game, err := b.box()
+	if err != nil {
+		return b, err
+	}
+	return m.applyBox(game), nil
+}
+func (a Archive) rescore() ([11]int, error) {
+	if a.Discipline != "" {
+		game, err := a.box()
+		if err != nil {
+			return a.Scores, err
+		}
+		return game.Scores(), nil
+	}
+	game, err := a.game()
+	if err != nil {
+		return a.Scores, err
+	}
+	return game.Scores(), nil
+}
+
+// validate lists every problem in bowl without changing it. Saved scores are
+// never trusted: every game is replayed with the scoring engine and compared,
+// so hand-edited files and games saved under older rules are caught.
+func validate(bowl Bowl) []finding {
+	var findings []finding
+	if bowl.Name == "" {
+		findings = append(findings, finding{Game: currentGame, Problem: "the player has no name"})
+	}
+	if _, err := scoring.LookupDiscipline(bowl.Discipline); err != nil && !bowl.tenPin() {
+		findings = append(findings, finding{Game: currentGame, Problem: fmt.Sprintf("unknown discipline %q", bowl.Discipline)})
+	} else if rescored, err := bowl.rescore(); err != nil {
+		findings = append(findings, recordFinding(currentGame, err))
+	} else {
+		if bowl.Times != rescored.Times {
+			findings = append(findings, finding{Game: currentGame, Problem: fmt.Sprintf("%d rolls are counted but %d were rolled", bowl.Times, rescored.Times)})
+		}
+		if bowl.MaxScore > perfect(bowl.Discipline) || bowl.MaxScore < 0 {
+			findings = append(findings, finding{Game: currentGame, Problem: fmt.Sprintf("the maximum score of %d is impossible", bowl.MaxScore)})
+		}
+		findings = append(findings, scoresFinding(currentGame, bowl.Scores, rescored.Scores)...)
+	}
+	for i, a := range bowl.Archives {
+		if _, err := scoring.LookupDiscipline(a.Discipline); err != nil && a.Discipline != "" {
+			findings = append(findings, finding{Game: i, Problem: fmt.Sprintf("unknown discipline %q", a.Discipline)})
+			continue
+		}
+		scores, err := a.rescore()
+		if err != nil {
+			findings = append(findings, recordFinding(i, err))
+			continue
+		}
+		findings = append(findings, scoresFinding(i, a.Scores, scores)...)
+	}
+	return findings
+}
+
+// resetGame empties the game in progress, keeping the discipline, rules and
+// variant that are known ones.
+func (b Bowl) resetGame() Bowl {
+	if _, err := scoring.LookupDiscipline(b.Discipline); err != nil {
+		b.Discipline = ""
+	}
+	if _, err := scoring.LookupRules(b.Rules); err != nil {
+		b.Rules = ""
+	}
+	if _, err := scoring.LookupVariant(b.Variant); err != nil {
+		b.Variant = ""
+	}
+	b.Pins = initPins()
+	b.Marks = nil
+	if !b.tenPin() {
+		b.Marks = initMarks()
+	}
+	b.Leaves = nil
+	b.Scores = initScores()
+	b.MaxScore = perfect(b.Discipline)
+	b.Times = 0
+	return b
+}
+
+// repair fixes what the rolls themselves tell: the game in progress keeps
+// its rolls up to the first bad one and is rescored, and archives whose rolls
+// replay are rescored. Archives whose rolls do not replay are kept as they
+// are, since nothing in them says what was really bowled.
+func (m Model) repair(findings []finding) Bowl {
+	bowl := m.Bowl
+	if bowl.Name == "" {
+		bowl.Name = strings.TrimSuffix(filepath.Base(m.data), ".json")
+	}
+	for _, f := range findings {
+		if f.Game != currentGame {
+			if scores, err := bowl.Archives[f.Game].rescore(); err == nil {
+				bowl.Archives[f.Game].Scores = scores
+			}
+			continue
+		}
+		last := scoring.BoxSlots + 1
+		for {
+			rescored, err := bowl.rescore()
+			if err == nil {
+				bowl = rescored
+				break
+			}
+			var rollErr *scoring.RollError
+			if !errors.As(err, &rollErr) || rollErr.Roll >= last {
+				bowl = bowl.resetGame()
+				continue
+			}
+			last = rollErr.Roll
+			bowl = bowl.truncate(rollErr.Roll - 1)
+		}
+	}
+	return bowl
+}
+
+// truncate drops the rolls of the game in progress from slot on.
+func (b Bowl) truncate(slot int) Bowl {
+	if b.tenPin() {
+		for i := slot; i < len(b.Pins); i++ {
+			b.Pins[i] = "yet"
+		}
+		if slot < len(b.Leaves) {
+			b.Leaves = b.Leaves[:slot]
+		}
+		return b
+	}
+	b.Marks = append([]string(nil), b.Marks...)
+	for i := slot; i < len(b.Marks); i++ {
+		b.Marks[i] = "yet"
+	}
+	return b
+}
+
+// discard throws away every game with a finding: archives are removed and the
+// game in progress starts over.
+func (m Model) discard(findings []finding) Bowl {
+	bowl := m.Bowl
+	if bowl.Name == "" {
+		bowl.Name = strings.TrimSuffix(filepath.Base(m.data), ".json")
+	}
+	drop := map[int]bool{}
+	for _, f := range findings {
+		drop[f.Game] = true
+	}
+	if drop[currentGame] {
+		bowl = bowl.resetGame()
+	}
+	var archives []Archive
+	for i, a := range bowl.Archives {
+		if !drop[i] {
+			archives = append(archives, a)
+		}
+	}
+	bowl.Archives = archives
+	return bowl
+}
+func (m Model) validateCommand(args []string, stdout io.Writer) error {
+	names := args
+	if len(names) == 0 {
+		var err error
+		if names, err = m.store.Players(); err != nil {
+			return err
+		}
+	}
+	count := 0
+	for _, name := range names {
+		bowl, err := m.store.Load(name)
+		if err != nil {
+			count++
+			fmt.Fprintf(stdout, "%s: %v\n", name, err)
+			continue
+		}
+		for _, f := range validate(bowl) {
+			count++
+			fmt.Fprintf(stdout, "%s: %s\n", name, f)
+		}
+	}
+	if count > 0 {
+		return fmt.Errorf("found %d problems in %d players", count, len(names))
+	}
+	fmt.Fprintf(stdout, "No problems in %d players.\n", len(names))
+	return nil
+}
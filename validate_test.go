@@ -0,0 +1,111 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func findingsOf(t *testing.T, m Model, name string) (Bowl, []finding) {
+	t.Helper()
+	m.data = name
+	bowl, findings, err := m.inspect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bowl, findings
+}
+
+// brokenPlayer saves a player with a rescorable archive, an archive whose
+// rolls do not replay and a game in progress with a bad third roll.
+func brokenPlayer(t *testing.T, m Model) {
+	t.Helper()
+	bowl := initBowl()
+	bowl.Name = "carol"
+	rescorable := testArchive(t, "9- 9- 9- 9- 9- 9- 9- 9- 9- 9-", "2024/01/01")
+	rescorable.Scores[3] = 999
+	unplayable := testArchive(t, "X X X X X X X X X XXX", "2024/01/02")
+	unplayable.Pins[0] = "11"
+	bowl.Archives = []Archive{rescorable, unplayable, testArchive(t, "5/ 5/ 5/ 5/ 5/ 5/ 5/ 5/ 5/ 5/5", "2024/01/03")}
+	copy(bowl.Pins[:], []string{"3", "4", "X", "5"})
+	bowl.Times = 4
+	m.Bowl = bowl
+	if err := m.write(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	m := testModel(t)
+	brokenPlayer(t, m)
+	bowl, findings := findingsOf(t, m, "carol")
+	if len(findings) != 3 {
+		t.Fatalf("findings = %v, want 3", findings)
+	}
+	for i, want := range []finding{
+		{Game: currentGame, Roll: 4, Problem: `"5" is not a possible roll here`},
+		{Game: 0, Problem: "frame 3 is saved as 999 but the rolls give 27"},
+		{Game: 1, Roll: 1, Problem: `"11" is not a possible roll here`},
+	} {
+		found := false
+		for _, f := range findings {
+			found = found || f == want
+		}
+		if !found {
+			t.Errorf("finding %d: no %v in %v", i, want, findings)
+		}
+	}
+	if !anyFatal(findings) {
+		t.Error("a bad game in progress is not fatal")
+	}
+
+	m.Bowl = bowl
+	repaired := m.repair(findings)
+	if got := validate(repaired); len(got) != 1 || got[0].Game != 1 {
+		t.Errorf("after repair = %v, want only the unplayable archive", got)
+	}
+	if repaired.Archives[0].Scores[3] != 27 || repaired.Times != 4 || repaired.Pins[3] != "yet" {
+		t.Errorf("repair = %v %v, times %d", repaired.Archives[0].Scores, repaired.Pins, repaired.Times)
+	}
+
+	discarded := m.discard(findings)
+	if got := validate(discarded); len(got) != 0 {
+		t.Errorf("after discard = %v", got)
+	}
+	if len(discarded.Archives) != 1 || discarded.Archives[0].Time != "2024/01/03" || discarded.Times != 0 {
+		t.Errorf("discard kept %+v, times %d", discarded.Archives, discarded.Times)
+	}
+}
+
+func TestLoadClean(t *testing.T) {
+	m := testModel(t)
+	brokenPlayer(t, m)
+	if _, err := m.loadClean("carol"); !errors.Is(err, errUnresolved) {
+		t.Errorf("loadClean() with a bad game in progress = %v, want %v", err, errUnresolved)
+	}
+	if _, err := m.loadOrCreate("carol"); !errors.Is(err, errUnresolved) {
+		t.Errorf("loadOrCreate() with a bad game in progress = %v, want %v", err, errUnresolved)
+	}
+
+	// Keeping the archives as they are is allowed once the game is repaired.
+	bowl, findings := findingsOf(t, m, "carol")
+	m.Bowl = bowl
+	m.Bowl = m.repair(findings)
+	if err := m.write(); err != nil {
+		t.Fatal(err)
+	}
+	bowl, err := m.loadClean("carol")
+	if err != nil {
+		t.Fatalf("loadClean() with problems only in the archives = %v", err)
+	}
+	if bowl.Archives[1].Pins[0] != "11" {
+		t.Error("loadClean() repaired an archive")
+	}
+
+	if _, err := m.loadClean("dave"); !errors.Is(err, errNoPlayer) {
+		t.Errorf("loadClean(dave) = %v, want %v", err, errNoPlayer)
+	}
+	bowl, err = m.loadOrCreate("dave")
+	if err != nil || bowl.Name != "dave" {
+		t.Errorf("loadOrCreate(dave) = %q, %v", bowl.Name, err)
+	}
+}